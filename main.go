@@ -4,19 +4,32 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/syslog"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"syscall"
+	"time"
 
-	"secrets-init/pkg/secrets" //nolint:gci
+	"secrets-init/pkg/redact" //nolint:gci
+	"secrets-init/pkg/secrets"
+	secretsage "secrets-init/pkg/secrets/age"
 	"secrets-init/pkg/secrets/aws"
+	"secrets-init/pkg/secrets/cache"
+	secretsexec "secrets-init/pkg/secrets/exec"
+	"secrets-init/pkg/secrets/file"
 	"secrets-init/pkg/secrets/google"
+	"secrets-init/pkg/secrets/kubernetes"
+	"secrets-init/pkg/secrets/sops"
+	"secrets-init/pkg/tracing"
 
+	"filippo.io/age"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	logsyslog "github.com/sirupsen/logrus/hooks/syslog"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/sys/unix" //nolint:gci
 )
@@ -32,40 +45,14 @@ var (
 	GitBranch = "dirty"
 	// Platform OS/ARCH
 	Platform = ""
+	// redactor masks resolved secret values in log output
+	redactor = redact.NewRedactor()
 )
 
 func main() {
 	app := &cli.App{
-		Before: setLogFormatter,
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "log-format, l",
-				Usage:   "select logrus formatter ['json', 'text']",
-				Value:   "text",
-				EnvVars: []string{"SECRETS_INIT_LOG_FORMAT", "LOG_FORMAT"},
-			},
-			&cli.StringFlag{
-				Name:    "provider, p",
-				Usage:   "supported secrets manager provider ['aws', 'google']",
-				Value:   "aws",
-				EnvVars: []string{"SECRETS_INIT_SECRETS_PROVIDER", "SECRETS_PROVIDER"},
-			},
-			&cli.BoolFlag{
-				Name:    "exit-early",
-				Usage:   "exit when a provider fails or a secret is not found",
-				EnvVars: []string{"SECRETS_INIT_EXIT_EARLY", "EXIT_EARLY"},
-			},
-			&cli.StringFlag{
-				Name:    "google-project",
-				Usage:   "the google cloud project for secrets without a project prefix",
-				EnvVars: []string{"SECRETS_INIT_GOOGLE_PROJECT", "GOOGLE_PROJECT"},
-			},
-			&cli.BoolFlag{
-				Name:    "interactive",
-				Aliases: []string{"i"},
-				Usage:   "use this flag if the command expects some input from the stdin",
-			},
-		},
+		Before: setLogger,
+		Flags:  flags(),
 		Commands: []*cli.Command{
 			{
 				Name:      "copy",
@@ -95,6 +82,219 @@ func main() {
 	}
 }
 
+// flags returns all secrets-init flags
+func flags() []cli.Flag {
+	f := generalFlags()
+	f = append(f, cloudFlags()...)
+	f = append(f, localFlags()...)
+	return append(f, runtimeFlags()...)
+}
+
+// generalFlags general flags: logging and the cloud secrets provider
+func generalFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "log-format, l",
+			Usage:   "select logrus formatter ['json', 'text']",
+			Value:   "text",
+			EnvVars: []string{"SECRETS_INIT_LOG_FORMAT", "LOG_FORMAT"},
+		},
+		&cli.StringFlag{
+			Name:    "log-level",
+			Usage:   "select log level ['trace', 'debug', 'info', 'warning', 'error', 'fatal', 'panic']",
+			Value:   "info",
+			EnvVars: []string{"SECRETS_INIT_LOG_LEVEL", "LOG_LEVEL"},
+		},
+		&cli.StringFlag{
+			Name:    "log-output",
+			Usage:   "select log destination ['stderr', 'file:<path>', 'syslog']",
+			Value:   "stderr",
+			EnvVars: []string{"SECRETS_INIT_LOG_OUTPUT"},
+		},
+		&cli.StringFlag{
+			Name:    "provider, p",
+			Usage:   "supported secrets manager provider ['aws', 'google']",
+			Value:   "aws",
+			EnvVars: []string{"SECRETS_INIT_SECRETS_PROVIDER", "SECRETS_PROVIDER"},
+		},
+		&cli.BoolFlag{
+			Name:    "exit-early",
+			Usage:   "exit when a provider fails or a secret is not found",
+			EnvVars: []string{"SECRETS_INIT_EXIT_EARLY", "EXIT_EARLY"},
+		},
+	}
+}
+
+// cloudFlags AWS and Google secrets provider flags
+func cloudFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "aws-assume-role",
+			Usage:   "role to assume for AWS secrets of another account 'ACCOUNT=ROLE_ARN' or 'ROLE_ARN' for the role account (repeatable)",
+			EnvVars: []string{"SECRETS_INIT_AWS_ASSUME_ROLE"},
+		},
+		&cli.StringFlag{
+			Name:    "aws-endpoint-secretsmanager",
+			Usage:   "custom AWS Secrets Manager endpoint URL for the configured region, e.g. VPC endpoint or LocalStack",
+			EnvVars: []string{"SECRETS_INIT_AWS_ENDPOINT_SECRETSMANAGER"},
+		},
+		&cli.StringFlag{
+			Name:    "aws-endpoint-ssm",
+			Usage:   "custom AWS SSM endpoint URL for the configured region, e.g. VPC endpoint or LocalStack",
+			EnvVars: []string{"SECRETS_INIT_AWS_ENDPOINT_SSM"},
+		},
+		&cli.BoolFlag{
+			Name:    "aws-fips",
+			Usage:   "use AWS FIPS endpoints",
+			EnvVars: []string{"SECRETS_INIT_AWS_FIPS"},
+		},
+		&cli.BoolFlag{
+			Name:    "aws-dual-stack",
+			Usage:   "use AWS dual-stack (IPv4 and IPv6) endpoints",
+			EnvVars: []string{"SECRETS_INIT_AWS_DUAL_STACK"},
+		},
+		&cli.StringFlag{
+			Name:    "google-project",
+			Usage:   "the google cloud project for secrets without a project prefix",
+			EnvVars: []string{"SECRETS_INIT_GOOGLE_PROJECT", "GOOGLE_PROJECT"},
+		},
+		&cli.BoolFlag{
+			Name:    "google-expand-json",
+			Usage:   "expand top-level keys of JSON object Google secrets into environment variables",
+			EnvVars: []string{"SECRETS_INIT_GOOGLE_EXPAND_JSON"},
+		},
+		&cli.StringFlag{
+			Name:    "google-impersonate-service-account",
+			Usage:   "google service account email to impersonate",
+			EnvVars: []string{"SECRETS_INIT_GOOGLE_IMPERSONATE_SERVICE_ACCOUNT"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "google-impersonate-delegates",
+			Usage:   "google service accounts delegation chain to the impersonated service account (repeatable)",
+			EnvVars: []string{"SECRETS_INIT_GOOGLE_IMPERSONATE_DELEGATES"},
+		},
+		&cli.StringFlag{
+			Name:    "google-credentials-file",
+			Usage:   "google credentials file to use instead of Application Default Credentials",
+			EnvVars: []string{"SECRETS_INIT_GOOGLE_CREDENTIALS_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    "google-quota-project",
+			Usage:   "google project for quota and billing",
+			EnvVars: []string{"SECRETS_INIT_GOOGLE_QUOTA_PROJECT"},
+		},
+		&cli.BoolFlag{
+			Name:    "google-log-versions",
+			Usage:   "log the version resolved for every google secret (e.g. for version aliases or 'latest')",
+			EnvVars: []string{"SECRETS_INIT_GOOGLE_LOG_VERSIONS"},
+		},
+	}
+}
+
+// localFlags SOPS, age, local file and exec providers flags
+func localFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "sops-file",
+			Usage:   "SOPS encrypted file ('.env', '.json' or '.yaml') to merge into the environment (repeatable)",
+			EnvVars: []string{"SECRETS_INIT_SOPS_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    "sops-age-key-file",
+			Usage:   "file with age keys used to decrypt SOPS files",
+			EnvVars: []string{"SECRETS_INIT_SOPS_AGE_KEY_FILE", "SOPS_AGE_KEY_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    "age-key-file",
+			Usage:   "file with age identities used to decrypt 'age:' values (or " + secretsage.KeyEnv + " environment variable)",
+			EnvVars: []string{"SECRETS_INIT_AGE_KEY_FILE"},
+		},
+		&cli.BoolFlag{
+			Name:    "file",
+			Usage:   "resolve 'file:' references with the content of the referenced file",
+			EnvVars: []string{"SECRETS_INIT_FILE"},
+		},
+		&cli.BoolFlag{
+			Name:    "exec",
+			Usage:   "resolve 'exec:' references with the output of the referenced command",
+			EnvVars: []string{"SECRETS_INIT_EXEC"},
+		},
+		&cli.StringFlag{
+			Name:    "exec-plugin",
+			Usage:   "plugin binary resolving 'plugin:' references with JSON stdin/stdout protocol",
+			EnvVars: []string{"SECRETS_INIT_EXEC_PLUGIN"},
+		},
+		&cli.DurationFlag{
+			Name:    "exec-timeout",
+			Usage:   "timeout for exec commands and plugin calls",
+			Value:   30 * time.Second, //nolint:gomnd
+			EnvVars: []string{"SECRETS_INIT_EXEC_TIMEOUT"},
+		},
+	}
+}
+
+// runtimeFlags secrets cache, tracing and runtime flags
+func runtimeFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "cache-dir",
+			Usage:   "cache resolved secrets encrypted in this directory (e.g. an emptyDir volume) across restarts",
+			EnvVars: []string{"SECRETS_INIT_CACHE_DIR"},
+		},
+		&cli.StringFlag{
+			Name:    "cache-key-file",
+			Usage:   "file with the data key used to encrypt the secrets cache",
+			EnvVars: []string{"SECRETS_INIT_CACHE_KEY_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    "cache-key-kms",
+			Usage:   "KMS encrypted data key used to encrypt the secrets cache ('awskms:CIPHERTEXT' or 'gcpkms:KEY_NAME:CIPHERTEXT'), decrypted with the provider KMS",
+			EnvVars: []string{"SECRETS_INIT_CACHE_KEY_KMS"},
+		},
+		&cli.DurationFlag{
+			Name:    "cache-ttl",
+			Usage:   "time to keep resolved secrets in the cache",
+			Value:   10 * time.Minute, //nolint:gomnd
+			EnvVars: []string{"SECRETS_INIT_CACHE_TTL"},
+		},
+		&cli.BoolFlag{
+			Name:    "no-cache",
+			Usage:   "do not use the secrets cache",
+			EnvVars: []string{"SECRETS_INIT_NO_CACHE"},
+		},
+		&cli.BoolFlag{
+			Name:    "interactive",
+			Aliases: []string{"i"},
+			Usage:   "use this flag if the command expects some input from the stdin",
+		},
+		&cli.BoolFlag{
+			Name:    "otel-traces",
+			Usage:   "export OpenTelemetry traces of secrets resolution with OTLP/HTTP",
+			EnvVars: []string{"SECRETS_INIT_OTEL_TRACES"},
+		},
+		&cli.StringFlag{
+			Name:    "otel-endpoint",
+			Usage:   "OTLP/HTTP collector endpoint host:port (default: OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318)",
+			EnvVars: []string{"SECRETS_INIT_OTEL_ENDPOINT"},
+		},
+		&cli.BoolFlag{
+			Name:    "otel-insecure",
+			Usage:   "disable TLS for the OTLP/HTTP collector connection",
+			EnvVars: []string{"SECRETS_INIT_OTEL_INSECURE"},
+		},
+		&cli.BoolFlag{
+			Name:    "otel-record-names",
+			Usage:   "record secret names as span attributes",
+			EnvVars: []string{"SECRETS_INIT_OTEL_RECORD_NAMES"},
+		},
+		&cli.BoolFlag{
+			Name:    "unsafe-log-secrets",
+			Usage:   "do not mask resolved secret values in log output (unsafe: for troubleshooting only)",
+			EnvVars: []string{"SECRETS_INIT_UNSAFE_LOG_SECRETS"},
+		},
+	}
+}
+
 func copyCmd(c *cli.Context) error {
 	if c.Args().Len() != 1 {
 		return errors.New("must specify copy destination")
@@ -136,35 +336,195 @@ func copyCmd(c *cli.Context) error {
 	return nil
 }
 
+// tracesFlushTimeout limits exporting pending spans before reaping zombies
+const tracesFlushTimeout = 5 * time.Second
+
 func mainCmd(c *cli.Context) error {
 	ctx := context.Background()
 
+	// setup tracing
+	shutdownTracing := func(context.Context) error { return nil }
+	if c.Bool("otel-traces") {
+		var err error
+		shutdownTracing, err = tracing.Setup(ctx, tracing.Options{
+			Endpoint:    c.String("otel-endpoint"),
+			Insecure:    c.Bool("otel-insecure"),
+			RecordNames: c.Bool("otel-record-names"),
+			Version:     Version,
+		})
+		if err != nil {
+			log.WithError(err).Error("failed to setup tracing")
+			shutdownTracing = func(context.Context) error { return nil }
+		}
+	}
+
 	// get provider
+	provider := newProvider(ctx, c)
+
+	// Launch main command
+	childPid, err := run(ctx, provider, c.Bool("exit-early"), c.Bool("interactive"), c.Args().Slice())
+	// flush traces; an unreachable collector must not delay reaping the child process
+	flushCtx, cancel := context.WithTimeout(ctx, tracesFlushTimeout)
+	if e := shutdownTracing(flushCtx); e != nil {
+		log.WithError(e).Warn("failed to export traces")
+	}
+	cancel()
+	if err != nil {
+		log.WithError(err).Error("failed to run")
+		os.Exit(1)
+	}
+
+	// Routine to reap zombies (it's the job of init)
+	removeZombies(childPid)
+	return nil
+}
+
+// newProvider init secrets provider chain: the selected cloud provider and local providers
+func newProvider(ctx context.Context, c *cli.Context) secrets.Provider {
+	provider := newCloudProvider(ctx, c)
+
+	// SOPS master keys using the cloud provider KMS clients
+	keys := &sops.MasterKeys{}
+	if p, ok := provider.(sops.AWSKMSProvider); ok {
+		keys.AWS = p
+	}
+	if p, ok := provider.(sops.GoogleKMSProvider); ok {
+		keys.Google = p
+	}
+	var err error
+	if keys.Age, err = loadAgeIdentities(c.String("sops-age-key-file"), sopsAgeKeyEnv); err != nil {
+		log.WithError(err).Error("failed to load SOPS age keys")
+	}
+
+	// wrap the cloud provider with secrets cache
+	if c.String("cache-dir") != "" && !c.Bool("no-cache") && provider != nil {
+		provider = newCachingProvider(ctx, c, provider)
+	}
+	return secrets.NewChainProvider(append([]secrets.Provider{provider}, newLocalProviders(c, keys)...)...)
+}
+
+// newCloudProvider init the selected cloud secrets provider; nil if no provider is selected or it fails
+// to initialize (exiting with --exit-early)
+func newCloudProvider(ctx context.Context, c *cli.Context) secrets.Provider {
 	var provider secrets.Provider
 	var err error
-	if c.String("provider") == "aws" {
-		provider, err = aws.NewAwsSecretsProvider()
-	} else if c.String("provider") == "google" {
-		provider, err = google.NewGoogleSecretsProvider(ctx, c.String("google-project"))
+	switch c.String("provider") {
+	case "aws":
+		var roles map[string]string
+		if roles, err = aws.ParseAssumeRoles(c.StringSlice("aws-assume-role")); err == nil {
+			provider, err = aws.NewAwsSecretsProvider(ctx, aws.Options{
+				AssumeRoles:            roles,
+				SecretsManagerEndpoint: c.String("aws-endpoint-secretsmanager"),
+				SSMEndpoint:            c.String("aws-endpoint-ssm"),
+				FIPS:                   c.Bool("aws-fips"),
+				DualStack:              c.Bool("aws-dual-stack"),
+			})
+		}
+	case "google":
+		provider, err = google.NewGoogleSecretsProvider(ctx, google.Options{
+			ProjectID:                 c.String("google-project"),
+			ExpandJSON:                c.Bool("google-expand-json"),
+			ImpersonateServiceAccount: c.String("google-impersonate-service-account"),
+			Delegates:                 c.StringSlice("google-impersonate-delegates"),
+			CredentialsFile:           c.String("google-credentials-file"),
+			QuotaProject:              c.String("google-quota-project"),
+			LogVersions:               c.Bool("google-log-versions"),
+		})
 	}
 	if err != nil {
 		log.WithField("provider", c.String("provider")).WithError(err).Error("failed to initialize secrets provider")
 		if c.Bool("exit-early") {
 			os.Exit(1)
 		}
+		return nil
 	}
+	return provider
+}
 
-	// Launch main command
-	var childPid int
-	childPid, err = run(ctx, provider, c.Bool("exit-early"), c.Bool("interactive"), c.Args().Slice())
+// newLocalProviders init the providers chained after the cloud provider, in order: SOPS, age, local file,
+// Kubernetes and exec
+func newLocalProviders(c *cli.Context, keys *sops.MasterKeys) []secrets.Provider {
+	providers := []secrets.Provider{sops.NewSopsSecretsProvider(c.StringSlice("sops-file"), keys)}
+
+	// add age provider when age identities are set
+	identities, err := loadAgeIdentities(c.String("age-key-file"), secretsage.KeyEnv)
 	if err != nil {
-		log.WithError(err).Error("failed to run")
-		os.Exit(1)
+		log.WithError(err).Error("failed to load age identities")
+	}
+	if len(identities) > 0 {
+		providers = append(providers, secretsage.NewAgeSecretsProvider(identities))
 	}
 
-	// Routine to reap zombies (it's the job of init)
-	removeZombies(childPid)
-	return nil
+	// add local file provider
+	if c.Bool("file") {
+		providers = append(providers, file.NewFileSecretsProvider())
+	}
+
+	// add Kubernetes provider when running inside a Kubernetes cluster
+	if k8s, e := kubernetes.NewKubernetesSecretsProvider(); e != nil {
+		log.WithError(e).Debug("Kubernetes secrets provider is not available")
+	} else {
+		providers = append(providers, k8s)
+	}
+
+	// add exec provider
+	if c.Bool("exec") || c.String("exec-plugin") != "" {
+		providers = append(providers, secretsexec.NewExecSecretsProvider(c.Bool("exec"), c.String("exec-plugin"), c.Duration("exec-timeout")))
+	}
+	return providers
+}
+
+// newCachingProvider wraps the cloud provider with the secrets cache encrypted with the data key read from
+// the key file or decrypted with the provider KMS; the provider is returned as is if the cache is not available
+func newCachingProvider(ctx context.Context, c *cli.Context, provider secrets.Provider) secrets.Provider {
+	p, ok := provider.(secrets.VariablesProvider)
+	if !ok {
+		return provider
+	}
+	var store *cache.FileStore
+	var err error
+	switch {
+	case c.String("cache-key-file") != "" && c.String("cache-key-kms") != "":
+		err = errors.New("only one of the cache key file and the KMS cache key can be set")
+	case c.String("cache-key-kms") != "":
+		var dataKey []byte
+		if dataKey, err = kmsDataKey(ctx, p, c.String("cache-key-kms")); err == nil {
+			store, err = cache.NewFileStore(c.String("cache-dir"), dataKey)
+		}
+	default:
+		store, err = cache.NewFileStoreWithKeyFile(c.String("cache-dir"), c.String("cache-key-file"))
+	}
+	if err != nil {
+		log.WithError(err).Warn("failed to initialize secrets cache; resolving secrets without cache")
+		return provider
+	}
+	return cache.NewCachingSecretsProvider(p, store, c.Duration("cache-ttl"))
+}
+
+// kmsDataKey decrypts the inline KMS ciphertext of the cache data key with the provider
+func kmsDataKey(ctx context.Context, provider secrets.Provider, ciphertext string) ([]byte, error) {
+	const env = "SECRETS_INIT_CACHE_KEY="
+	envs, err := provider.ResolveSecrets(ctx, []string{env + ciphertext})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt cache data key")
+	}
+	if len(envs) != 1 || envs[0] == env+ciphertext || !strings.HasPrefix(envs[0], env) {
+		return nil, errors.New("cache data key is not a KMS ciphertext of the selected provider")
+	}
+	return []byte(strings.TrimPrefix(envs[0], env)), nil
+}
+
+// sopsAgeKeyEnv environment variable with SOPS age identities, as read by the sops command
+const sopsAgeKeyEnv = "SOPS_AGE_KEY"
+
+// loadAgeIdentities loads age identities from the key file and the keyEnv variable; the variable is unset,
+// so the identities are not passed to the child process even if secrets resolution fails
+func loadAgeIdentities(keyFile, keyEnv string) ([]age.Identity, error) {
+	keys := os.Getenv(keyEnv)
+	if err := os.Unsetenv(keyEnv); err != nil {
+		return nil, errors.Wrap(err, "failed to unset age identities variable")
+	}
+	return secretsage.LoadIdentities(keyFile, keys) //nolint:wrapcheck
 }
 
 func removeZombies(childPid int) {
@@ -231,19 +591,18 @@ func run(ctx context.Context, provider secrets.Provider, exitEarly, interactive
 	cmd.SysProcAttr = procAttrs
 
 	// set environment variables
-	if provider != nil {
-		cmd.Env, err = provider.ResolveSecrets(ctx, os.Environ())
-		if err != nil {
-			log.WithError(err).Error("failed to resolve secrets")
-			if exitEarly {
-				log.Error("Exiting early unable to retrieve secrets")
-				os.Exit(1)
-			}
+	resolveCtx, span := tracing.Start(ctx, "secrets-init.ResolveSecrets")
+	cmd.Env, err = provider.ResolveSecrets(resolveCtx, os.Environ())
+	tracing.End(span, err)
+	if err != nil {
+		log.WithError(err).Error("failed to resolve secrets")
+		if exitEarly {
+			log.Error("Exiting early unable to retrieve secrets")
+			os.Exit(1)
 		}
-	} else {
-		log.Warn("no secrets provider available; using environment without resolving secrets")
-		cmd.Env = os.Environ()
 	}
+	// mask resolved secret values in all further log output
+	redactor.Add(redact.ResolvedValues(os.Environ(), cmd.Env)...)
 
 	// start the specified command
 	log.WithFields(log.Fields{
@@ -283,11 +642,66 @@ func run(ctx context.Context, provider secrets.Provider, exitEarly, interactive
 	return childPid, nil
 }
 
-func setLogFormatter(c *cli.Context) error {
+// componentHook adds the component field to every log entry, so secrets-init logs can be told apart from child logs
+type componentHook struct{}
+
+func (componentHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (componentHook) Fire(entry *log.Entry) error {
+	entry.Data["component"] = "secrets-init"
+	return nil
+}
+
+func setLogger(c *cli.Context) error {
+	// set log formatter
+	var formatter log.Formatter = &log.TextFormatter{}
 	if c.String("log-format") == "json" {
-		log.SetFormatter(&log.JSONFormatter{})
-	} else if c.String("log-format") == "text" {
-		log.SetFormatter(&log.TextFormatter{})
+		formatter = &log.JSONFormatter{}
+	}
+	if !c.Bool("unsafe-log-secrets") {
+		formatter = &redact.Formatter{Formatter: formatter, Redactor: redactor}
+	}
+	log.SetFormatter(formatter)
+	// component hook must fire before any output hook
+	log.AddHook(componentHook{})
+
+	// set log level; an invalid level (e.g. 'notice' from the LOG_LEVEL variable of the child) falls back to info
+	level, levelErr := log.ParseLevel(c.String("log-level"))
+	if levelErr != nil {
+		level = log.InfoLevel
+	}
+	log.SetLevel(level)
+
+	// set log output
+	output := c.String("log-output")
+	switch {
+	case output == "stderr":
+		log.SetOutput(os.Stderr)
+	case output == "syslog":
+		hook, err := logsyslog.NewSyslogHook("", "", syslog.LOG_INFO|syslog.LOG_DAEMON, "secrets-init")
+		if err != nil {
+			return errors.Wrap(err, "failed to connect to syslog")
+		}
+		log.AddHook(hook)
+		log.SetOutput(io.Discard)
+	case strings.HasPrefix(output, "file:"):
+		path := strings.TrimPrefix(output, "file:")
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) //nolint:gomnd
+		if err != nil {
+			return errors.Wrapf(err, "failed to open log file %s", path)
+		}
+		log.SetOutput(file)
+	default:
+		return errors.Errorf("unsupported log output %q", output)
+	}
+
+	if levelErr != nil {
+		log.WithError(levelErr).Warn("invalid log level; using info")
+	}
+	if c.Bool("unsafe-log-secrets") {
+		log.Warn("secret values are not masked in log output")
 	}
 	return nil
 }
@@ -0,0 +1,94 @@
+// nolint
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"secrets-init/pkg/secrets"
+	secretsage "secrets-init/pkg/secrets/age"
+	secretsexec "secrets-init/pkg/secrets/exec"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingProvider fails like a cloud provider without credentials
+type failingProvider struct{}
+
+func (failingProvider) ResolveSecrets(_ context.Context, vars []string) ([]string, error) {
+	return vars, errors.New("test error")
+}
+
+// cloudProvider resolves 'cloud:' prefixed values with the values of secrets
+type cloudProvider map[string]string
+
+func (p cloudProvider) ResolveSecrets(_ context.Context, vars []string) ([]string, error) {
+	envs := make([]string, 0, len(vars))
+	for _, env := range vars {
+		key, value, _ := strings.Cut(env, "=")
+		if secret, ok := p[strings.TrimPrefix(value, "cloud:")]; ok && strings.HasPrefix(value, "cloud:") {
+			env = key + "=" + secret
+		}
+		envs = append(envs, env)
+	}
+	return envs, nil
+}
+
+func TestChain_ResolvedValuesNotResolvedAgain(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "executed")
+	provider := secrets.NewChainProvider(
+		cloudProvider{"db-password": "exec:touch " + marker},
+		secretsexec.NewExecSecretsProvider(true, "", time.Second),
+	)
+	got, err := provider.ResolveSecrets(context.TODO(), []string{"DB_PASSWORD=cloud:db-password", "GREETING=exec:echo hello"})
+	require.NoError(t, err)
+	// the cloud secret value is passed as is, only the original exec reference is run
+	assert.ElementsMatch(t, []string{"DB_PASSWORD=exec:touch " + marker, "GREETING=hello"}, got)
+	assert.NoFileExists(t, marker)
+}
+
+func TestRun_AgeKeyNotPassedOnFailure(t *testing.T) {
+	defer signal.Reset()
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	t.Setenv(secretsage.KeyEnv, identity.String())
+
+	identities, err := loadAgeIdentities("", secretsage.KeyEnv)
+	require.NoError(t, err)
+	assert.Len(t, identities, 1)
+
+	// the age provider, removing the variable itself, is not reached when an earlier provider fails
+	provider := secrets.NewChainProvider(failingProvider{}, secretsage.NewAgeSecretsProvider(identities))
+	out := filepath.Join(t.TempDir(), "env")
+	childPid, err := run(context.TODO(), provider, false, false, []string{"/bin/sh", "-c", "env > " + out})
+	require.NoError(t, err)
+	var status syscall.WaitStatus
+	_, err = syscall.Wait4(childPid, &status, 0, nil)
+	require.NoError(t, err)
+
+	env, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.NotContains(t, string(env), secretsage.KeyEnv)
+	assert.NotContains(t, string(env), identity.String())
+}
+
+func TestLoadAgeIdentities_SOPSKeyUnset(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	t.Setenv(sopsAgeKeyEnv, identity.String())
+
+	identities, err := loadAgeIdentities("", sopsAgeKeyEnv)
+	require.NoError(t, err)
+	assert.Len(t, identities, 1)
+	_, ok := os.LookupEnv(sopsAgeKeyEnv)
+	assert.False(t, ok)
+}
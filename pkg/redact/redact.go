@@ -0,0 +1,143 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Mask replaces secret values in log output
+const Mask = "******"
+
+// Redactor keeps track of secret values that must never appear in log output
+type Redactor struct {
+	mu       sync.RWMutex
+	values   []string
+	replacer *strings.Replacer
+}
+
+// NewRedactor init empty Redactor
+func NewRedactor() *Redactor {
+	return &Redactor{}
+}
+
+// Add registers secret values to be masked, with their JSON and Go quoted escaped forms used by
+// log formatters; empty values are ignored
+func (r *Redactor) Add(values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, v := range values {
+		if v != "" {
+			r.values = append(r.values, escapedForms(v)...)
+		}
+	}
+	// replace longer values first, so a secret containing another secret is masked entirely
+	sort.Slice(r.values, func(i, j int) bool { return len(r.values[i]) > len(r.values[j]) })
+	oldnew := make([]string, 0, len(r.values)*2) //nolint:gomnd
+	for _, v := range r.values {
+		oldnew = append(oldnew, v, Mask)
+	}
+	r.replacer = strings.NewReplacer(oldnew...)
+}
+
+// Redact returns s with all registered secret values masked
+func (r *Redactor) Redact(s string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.replacer == nil {
+		return s
+	}
+	return r.replacer.Replace(s)
+}
+
+// escapedForms returns value with its distinct JSON (with and without HTML escaping) and Go quoted escaped forms
+func escapedForms(value string) []string {
+	forms := []string{value}
+	add := func(quoted string) {
+		form := strings.TrimSuffix(strings.TrimPrefix(quoted, `"`), `"`)
+		for _, f := range forms {
+			if f == form {
+				return
+			}
+		}
+		forms = append(forms, form)
+	}
+	if b, err := json.Marshal(value); err == nil {
+		add(string(b))
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if encoder.Encode(value) == nil {
+		add(strings.TrimSuffix(buf.String(), "\n"))
+	}
+	add(strconv.Quote(value))
+	return forms
+}
+
+// Formatter logrus formatter that masks registered secret values in the entry message and fields before they
+// are formatted (and escaped) by the wrapped formatter, and in its output
+type Formatter struct {
+	log.Formatter
+	Redactor *Redactor
+}
+
+// Format formats the entry with masked secret values with the wrapped formatter and masks secret values in the result
+func (f *Formatter) Format(entry *log.Entry) ([]byte, error) {
+	redacted := *entry
+	redacted.Message = f.Redactor.Redact(entry.Message)
+	redacted.Data = make(log.Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		redacted.Data[k] = f.redactValue(v)
+	}
+	b, err := f.Formatter.Format(&redacted)
+	if err != nil {
+		return b, err //nolint:wrapcheck
+	}
+	return []byte(f.Redactor.Redact(string(b))), nil
+}
+
+// redactValue masks secret values in a log field value; errors and stringers are replaced by their masked text
+func (f *Formatter) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return f.Redactor.Redact(v)
+	case []string:
+		redacted := make([]string, len(v))
+		for i, s := range v {
+			redacted[i] = f.Redactor.Redact(s)
+		}
+		return redacted
+	case error:
+		return f.Redactor.Redact(v.Error())
+	case fmt.Stringer:
+		return f.Redactor.Redact(v.String())
+	default:
+		return value
+	}
+}
+
+// ResolvedValues returns values of the variables in after that were not passed unchanged in before,
+// i.e. values that were resolved by a secrets provider
+func ResolvedValues(before, after []string) []string {
+	passed := make(map[string]struct{}, len(before))
+	for _, env := range before {
+		passed[env] = struct{}{}
+	}
+	values := make([]string, 0, len(after))
+	for _, env := range after {
+		if _, ok := passed[env]; ok {
+			continue
+		}
+		if i := strings.Index(env, "="); i >= 0 {
+			values = append(values, env[i+1:])
+		}
+	}
+	return values
+}
@@ -0,0 +1,107 @@
+package age
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"secrets-init/pkg/secrets" //nolint:gci
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/pkg/errors" //nolint:gci
+)
+
+const (
+	agePrefix  = "age:"
+	filePrefix = "file:"
+	// KeyEnv environment variable with age identities (secret keys); removed from the resolved environment
+	KeyEnv = "SECRETS_INIT_AGE_KEY"
+)
+
+// SecretsProvider age encrypted values secrets provider; decrypts with local identities, without network access
+type SecretsProvider struct {
+	identities []age.Identity
+}
+
+// NewAgeSecretsProvider init age secrets provider
+func NewAgeSecretsProvider(identities []age.Identity) secrets.Provider {
+	return &SecretsProvider{identities: identities}
+}
+
+// LoadIdentities loads age identities (one per line) from the key file and the keys string (e.g. KeyEnv value)
+func LoadIdentities(keyFile, keys string) ([]age.Identity, error) {
+	if keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read age key file")
+		}
+		keys += "\n" + string(data)
+	}
+	if strings.TrimSpace(keys) == "" {
+		return nil, nil
+	}
+	identities, err := age.ParseIdentities(strings.NewReader(keys))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse age identities")
+	}
+	return identities, nil
+}
+
+// ResolveSecrets replaces all passed variables values prefixed with 'age:' by the decrypted value of the
+// inline armored age ciphertext or of the referenced age encrypted file (armored or binary)
+//
+//	`age:-----BEGIN AGE ENCRYPTED FILE-----...`
+//	`age:file:/etc/secrets/db-password.age`
+//
+// The KeyEnv variable is removed, so the age identities are not passed to the child process
+func (sp *SecretsProvider) ResolveSecrets(_ context.Context, vars []string) ([]string, error) {
+	envs := make([]string, 0, len(vars))
+	for _, env := range vars {
+		kv := strings.SplitN(env, "=", 2) //nolint:gomnd
+		if kv[0] == KeyEnv {
+			continue
+		}
+		if len(kv) == 2 && strings.HasPrefix(kv[1], agePrefix) {
+			value, err := sp.decrypt(strings.TrimPrefix(kv[1], agePrefix))
+			if err != nil {
+				return vars, errors.Wrapf(err, "failed to decrypt age value of %s", kv[0])
+			}
+			env = kv[0] + "=" + value
+		}
+		envs = append(envs, env)
+	}
+	return envs, nil
+}
+
+// decrypt decrypts inline or file age ciphertext
+func (sp *SecretsProvider) decrypt(ref string) (string, error) {
+	if len(sp.identities) == 0 {
+		return "", errors.New("no age identity")
+	}
+	var data []byte
+	if strings.HasPrefix(ref, filePrefix) {
+		var err error
+		if data, err = os.ReadFile(strings.TrimPrefix(ref, filePrefix)); err != nil {
+			return "", errors.Wrap(err, "failed to read age encrypted file")
+		}
+	} else {
+		data = []byte(ref)
+	}
+
+	var src io.Reader = bytes.NewReader(data)
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(armor.Header)) {
+		src = armor.NewReader(bytes.NewReader(bytes.TrimSpace(data)))
+	}
+	r, err := age.Decrypt(src, sp.identities...)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decrypt")
+	}
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decrypt")
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,149 @@
+// nolint
+package age
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encrypt(t *testing.T, recipient age.Recipient, plaintext string, armored bool) []byte {
+	var buf bytes.Buffer
+	var dst io.WriteCloser = nopCloser{&buf}
+	if armored {
+		dst = armor.NewWriter(&buf)
+	}
+	w, err := age.Encrypt(dst, recipient)
+	require.NoError(t, err)
+	_, err = io.WriteString(w, plaintext)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, dst.Close())
+	return buf.Bytes()
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+func TestSecretsProvider_ResolveSecrets(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	other, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	binaryFile := filepath.Join(dir, "secret.age")
+	require.NoError(t, os.WriteFile(binaryFile, encrypt(t, identity.Recipient(), "file-secret", false), 0o600))
+	armoredFile := filepath.Join(dir, "secret.age.asc")
+	require.NoError(t, os.WriteFile(armoredFile, encrypt(t, identity.Recipient(), "armored-secret", true), 0o600))
+	inline := string(encrypt(t, identity.Recipient(), "inline-secret", true))
+	wrongKey := string(encrypt(t, other.Recipient(), "inline-secret", true))
+
+	tests := []struct {
+		name       string
+		identities []age.Identity
+		vars       []string
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name:       "decrypt inline and file values",
+			identities: []age.Identity{identity},
+			vars: []string{
+				"test-inline=age:" + inline,
+				"test-file=age:file:" + binaryFile,
+				"test-armored=age:file:" + armoredFile,
+				"non-secret=hello",
+			},
+			want: []string{
+				"test-inline=inline-secret",
+				"test-file=file-secret",
+				"test-armored=armored-secret",
+				"non-secret=hello",
+			},
+		},
+		{
+			name:       "remove age key from environment",
+			identities: []age.Identity{identity},
+			vars: []string{
+				KeyEnv + "=" + identity.String(),
+				"non-secret=hello",
+			},
+			want: []string{
+				"non-secret=hello",
+			},
+		},
+		{
+			name:       "error wrong key",
+			identities: []age.Identity{identity},
+			vars: []string{
+				"test-inline=age:" + wrongKey,
+			},
+			want: []string{
+				"test-inline=age:" + wrongKey,
+			},
+			wantErr: true,
+		},
+		{
+			name: "error no identity",
+			vars: []string{
+				"test-inline=age:" + inline,
+			},
+			want: []string{
+				"test-inline=age:" + inline,
+			},
+			wantErr: true,
+		},
+		{
+			name:       "error missing file",
+			identities: []age.Identity{identity},
+			vars: []string{
+				"test-file=age:file:" + filepath.Join(dir, "missing.age"),
+			},
+			want: []string{
+				"test-file=age:file:" + filepath.Join(dir, "missing.age"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := NewAgeSecretsProvider(tt.identities)
+			got, err := sp.ResolveSecrets(context.TODO(), tt.vars)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SecretsProvider.ResolveSecrets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadIdentities(t *testing.T) {
+	fromEnv, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	fromFile, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "age.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("# created: test\n"+fromFile.String()+"\n"), 0o600))
+
+	identities, err := LoadIdentities(keyFile, fromEnv.String())
+	require.NoError(t, err)
+	assert.Len(t, identities, 2)
+
+	identities, err = LoadIdentities("", "")
+	require.NoError(t, err)
+	assert.Empty(t, identities)
+
+	_, err = LoadIdentities("", "not-a-key")
+	assert.Error(t, err)
+}
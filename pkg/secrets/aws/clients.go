@@ -0,0 +1,100 @@
+package aws
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/pkg/errors"
+)
+
+// clients AWS Secrets Manager and SSM clients for a region and an assumed role
+type clients struct {
+	sm  SecretsManagerAPI
+	ssm SSMAPI
+}
+
+// ParseAssumeRoles parses account to role mappings `ACCOUNT=ROLE_ARN`; the account of `ROLE_ARN` is used
+// if the account is omitted
+func ParseAssumeRoles(mappings []string) (map[string]string, error) {
+	roles := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		account, role, ok := strings.Cut(mapping, "=")
+		if !ok {
+			role = mapping
+		}
+		roleARN, err := arn.Parse(role)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid role ARN %q", role)
+		}
+		if !ok {
+			account = roleARN.AccountID
+		}
+		roles[account] = role
+	}
+	return roles, nil
+}
+
+// arnScope returns the region and the account of the ARN; empty if ref is not an ARN
+func arnScope(ref string) (region, account string) {
+	if a, err := arn.Parse(ref); err == nil {
+		return a.Region, a.AccountID
+	}
+	return "", ""
+}
+
+// clientsFor returns clients for the region (config region if empty) and the account: the default clients
+// for the config region and account and clients with the assumed account role otherwise
+func (sp *SecretsProvider) clientsFor(region, account string) *clients {
+	if region == "" {
+		region = sp.region()
+	}
+	role := sp.roles[account]
+	if region == sp.region() && role == "" {
+		return &clients{sm: sp.sm, ssm: sp.ssm}
+	}
+
+	sp.clientsMu.Lock()
+	defer sp.clientsMu.Unlock()
+	key := region + "|" + role
+	if c, ok := sp.clients[key]; ok {
+		return c
+	}
+	if sp.cfg == nil {
+		// no config to create clients from
+		return &clients{sm: sp.sm, ssm: sp.ssm}
+	}
+	cfg := sp.cfg.Copy()
+	cfg.Region = region
+	if role != "" {
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(*sp.cfg), role))
+	}
+	c := &clients{}
+	c.sm, c.ssm = sp.newClients(cfg)
+	if sp.clients == nil {
+		sp.clients = make(map[string]*clients)
+	}
+	sp.clients[key] = c
+	return c
+}
+
+// newClients creates Secrets Manager and SSM clients; the custom endpoints, if any, are regional (e.g. VPC
+// endpoints), so they are used only by clients for the config region, including assumed role clients
+func (sp *SecretsProvider) newClients(cfg aws.Config) (SecretsManagerAPI, SSMAPI) {
+	configRegion := cfg.Region == sp.region()
+	sm := secretsmanager.NewFromConfig(cfg, func(o *secretsmanager.Options) {
+		if sp.smEndpoint != "" && configRegion {
+			o.BaseEndpoint = aws.String(sp.smEndpoint)
+		}
+	})
+	ssmClient := ssm.NewFromConfig(cfg, func(o *ssm.Options) {
+		if sp.ssmEndpoint != "" && configRegion {
+			o.BaseEndpoint = aws.String(sp.ssmEndpoint)
+		}
+	})
+	return sm, ssmClient
+}
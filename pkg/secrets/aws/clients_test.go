@@ -0,0 +1,117 @@
+// nolint
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"secrets-init/mocks"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAssumeRoles(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings []string
+		want     map[string]string
+		wantErr  bool
+	}{
+		{
+			name:     "account to role",
+			mappings: []string{"111122223333=arn:aws:iam::444455556666:role/secrets-reader"},
+			want:     map[string]string{"111122223333": "arn:aws:iam::444455556666:role/secrets-reader"},
+		},
+		{
+			name:     "role account",
+			mappings: []string{"arn:aws:iam::111122223333:role/secrets-reader"},
+			want:     map[string]string{"111122223333": "arn:aws:iam::111122223333:role/secrets-reader"},
+		},
+		{
+			name:     "error invalid role ARN",
+			mappings: []string{"111122223333=secrets-reader"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAssumeRoles(tt.mappings)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseAssumeRoles() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSecretsProvider_ResolveSecrets_RegionAndAccount(t *testing.T) {
+	cfg := aws.Config{Region: "us-east-1", Credentials: credentials.NewStaticCredentialsProvider("id", "secret", "")}
+
+	const role = "arn:aws:iam::444455556666:role/secrets-reader"
+	defaultSM := mocks.NewSecretsManagerAPI(t)
+	regionSM := mocks.NewSecretsManagerAPI(t)
+	roleSSM := mocks.NewSSMAPI(t)
+	sp := &SecretsProvider{
+		cfg:   &cfg,
+		sm:    defaultSM,
+		roles: map[string]string{"444455556666": role},
+		clients: map[string]*clients{
+			"eu-west-1|":        {sm: regionSM},
+			"us-east-1|" + role: {ssm: roleSSM},
+		},
+	}
+
+	expectSecret := func(m *mocks.SecretsManagerAPI, name, value string) {
+		m.On("GetSecretValue", mock.Anything, &secretsmanager.GetSecretValueInput{SecretId: aws.String(name)}).
+			Return(&secretsmanager.GetSecretValueOutput{SecretString: aws.String(value)}, nil)
+	}
+	expectSecret(defaultSM, "arn:aws:secretsmanager:us-east-1:111122223333:secret:default", "default-value")
+	expectSecret(regionSM, "arn:aws:secretsmanager:eu-west-1:111122223333:secret:region", "region-value")
+	expectSecret(regionSM, "prod/db", "short-value")
+	roleSSM.On("GetParameter", mock.Anything, &ssm.GetParameterInput{Name: aws.String("/shared/key"), WithDecryption: aws.Bool(true)}).
+		Return(&ssm.GetParameterOutput{Parameter: &types.Parameter{Value: aws.String("shared-value")}}, nil)
+
+	got, err := sp.ResolveSecrets(context.TODO(), []string{
+		"default=arn:aws:secretsmanager:us-east-1:111122223333:secret:default",
+		"region=arn:aws:secretsmanager:eu-west-1:111122223333:secret:region",
+		"shared=arn:aws:ssm:us-east-1:444455556666:parameter/shared/key",
+		"short=aws:sm:prod/db?region=eu-west-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default=default-value", "region=region-value", "shared=shared-value", "short=short-value"}, got)
+}
+
+func TestSecretsProvider_clientsFor(t *testing.T) {
+	cfg := aws.Config{Region: "us-east-1", Credentials: credentials.NewStaticCredentialsProvider("id", "secret", "")}
+	sp := &SecretsProvider{
+		cfg:         &cfg,
+		roles:       map[string]string{"444455556666": "arn:aws:iam::444455556666:role/secrets-reader"},
+		smEndpoint:  "https://vpce-sm.us-east-1.vpce.amazonaws.com",
+		ssmEndpoint: "https://vpce-ssm.us-east-1.vpce.amazonaws.com",
+	}
+
+	regional := sp.clientsFor(arnScope("arn:aws:secretsmanager:eu-west-1:111122223333:secret:db"))
+	assert.Equal(t, "eu-west-1", regional.sm.(*secretsmanager.Client).Options().Region)
+	assert.Equal(t, cfg.Credentials, regional.sm.(*secretsmanager.Client).Options().Credentials)
+	assert.Same(t, regional, sp.clientsFor(arnScope("arn:aws:ssm:eu-west-1:111122223333:parameter/db")))
+	// custom endpoints are used only in the config region
+	assert.Nil(t, regional.sm.(*secretsmanager.Client).Options().BaseEndpoint)
+	assert.Nil(t, regional.ssm.(*ssm.Client).Options().BaseEndpoint)
+
+	assumed := sp.clientsFor(arnScope("arn:aws:secretsmanager:us-east-1:444455556666:secret:db"))
+	assert.NotSame(t, regional, assumed)
+	assert.Equal(t, "us-east-1", assumed.ssm.(*ssm.Client).Options().Region)
+	assert.IsType(t, &aws.CredentialsCache{}, assumed.ssm.(*ssm.Client).Options().Credentials)
+	assert.Equal(t, sp.smEndpoint, aws.ToString(assumed.sm.(*secretsmanager.Client).Options().BaseEndpoint))
+	assert.Equal(t, sp.ssmEndpoint, aws.ToString(assumed.ssm.(*ssm.Client).Options().BaseEndpoint))
+}
@@ -0,0 +1,131 @@
+// nolint
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// isolate AWS session from the local AWS configuration
+func setupAWSEnv(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_CA_BUNDLE", "")
+}
+
+// fakeAWS local TLS stand-in of the AWS Secrets Manager and SSM JSON APIs, trusted with AWS_CA_BUNDLE
+func fakeAWS(t *testing.T, secrets, params map[string]string) *httptest.Server {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			SecretId string
+			Name     string
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		switch r.Header.Get("X-Amz-Target") {
+		case "secretsmanager.GetSecretValue":
+			if value, ok := secrets[req.SecretId]; ok {
+				_ = json.NewEncoder(w).Encode(map[string]string{"ARN": req.SecretId, "SecretString": value})
+				return
+			}
+		case "AmazonSSM.GetParameter":
+			if value, ok := params[req.Name]; ok {
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"Parameter": map[string]string{"Name": req.Name, "Value": value}})
+				return
+			}
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"not found"}`))
+	}))
+	t.Cleanup(srv.Close)
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caBundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600))
+	t.Setenv("AWS_CA_BUNDLE", caBundle)
+	return srv
+}
+
+func TestNewAwsSecretsProvider_Endpoints(t *testing.T) {
+	setupAWSEnv(t)
+	srv := fakeAWS(t,
+		map[string]string{"arn:aws:secretsmanager:us-east-1:111122223333:secret:db": "db-password"},
+		map[string]string{"/api/key": "key-123456789"},
+	)
+	sp, err := NewAwsSecretsProvider(context.TODO(), Options{SecretsManagerEndpoint: srv.URL, SSMEndpoint: srv.URL})
+	require.NoError(t, err)
+
+	got, err := sp.ResolveSecrets(context.TODO(), []string{
+		"DB_PASSWORD=arn:aws:secretsmanager:us-east-1:111122223333:secret:db",
+		"API_KEY=arn:aws:ssm:us-east-1:111122223333:parameter/api/key",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"API_KEY=key-123456789", "DB_PASSWORD=db-password"}, got)
+
+	_, err = sp.ResolveSecrets(context.TODO(), []string{"DB_PASSWORD=arn:aws:secretsmanager:us-east-1:111122223333:secret:missing"})
+	assert.ErrorContains(t, err, "ResourceNotFoundException")
+}
+
+// hostRecorder HTTP client recording request hosts
+type hostRecorder struct {
+	hosts []string
+}
+
+func (r *hostRecorder) Do(req *http.Request) (*http.Response, error) {
+	r.hosts = append(r.hosts, req.URL.Host)
+	return &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.1"}},
+		Body:       io.NopCloser(strings.NewReader(`{"__type":"ResourceNotFoundException","message":"not found"}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestNewAwsSecretsProvider_FIPSAndDualStack(t *testing.T) {
+	setupAWSEnv(t)
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{
+			name: "default endpoints",
+			want: []string{"secretsmanager.us-east-1.amazonaws.com", "ssm.us-east-1.amazonaws.com"},
+		},
+		{
+			name: "FIPS endpoints",
+			opts: Options{FIPS: true},
+			want: []string{"secretsmanager-fips.us-east-1.amazonaws.com", "ssm-fips.us-east-1.amazonaws.com"},
+		},
+		{
+			name: "dual-stack endpoints",
+			opts: Options{DualStack: true},
+			// Secrets Manager endpoints are dual-stack
+			want: []string{"secretsmanager.us-east-1.amazonaws.com", "ssm.us-east-1.api.aws"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &hostRecorder{}
+			sp, err := newSecretsProvider(context.TODO(), tt.opts, config.WithHTTPClient(recorder))
+			require.NoError(t, err)
+			_, _ = sp.ResolveSecrets(context.TODO(), []string{"test-secret=aws:sm:db"})
+			_, _ = sp.ResolveSecrets(context.TODO(), []string{"test-param=aws:ssm:/db"})
+			assert.Equal(t, tt.want, recorder.hosts)
+		})
+	}
+}
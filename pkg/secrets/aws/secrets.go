@@ -2,105 +2,550 @@ package aws
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
 	"sort"
 	"strings"
+	"sync"
 
 	"secrets-init/pkg/secrets" //nolint:gci
+	"secrets-init/pkg/tracing"
 
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/secretsmanager"
-	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
-	"github.com/aws/aws-sdk-go/service/ssm"
-	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
-	"github.com/pkg/errors" //nolint:gci
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp" //nolint:gci
 )
 
 const (
-	paramNameTokens            = 6
-	paramNameTokensWithVersion = 7
+	kmsPrefix            = "awskms:"
+	secretsManagerPrefix = "aws:secretsmanager:"
+	smPrefix             = "aws:sm:"
+	ssmPrefix            = "aws:ssm:"
+
+	pathWildcard = "/*"
+
+	binaryBase64 = "base64"
+	binaryFile   = "file"
 )
 
+var (
+	// Secrets Manager and SSM ARNs of all AWS partitions: aws, aws-cn, aws-us-gov, aws-iso, aws-iso-b, ...
+	secretsManagerARNRe = regexp.MustCompile(`^arn:aws(-[a-z]+)*:secretsmanager:`)
+	ssmARNRe            = regexp.MustCompile(`^arn:aws(-[a-z]+)*:ssm:`)
+)
+
+// Options AWS secrets provider options
+type Options struct {
+	// AssumeRoles role ARNs to assume with STS for secrets of other accounts, by account ID
+	AssumeRoles map[string]string
+	// SecretsManagerEndpoint custom AWS Secrets Manager endpoint URL, e.g. VPC endpoint or local emulator
+	SecretsManagerEndpoint string
+	// SSMEndpoint custom AWS SSM endpoint URL, e.g. VPC endpoint or local emulator
+	SSMEndpoint string
+	// FIPS use FIPS endpoints
+	FIPS bool
+	// DualStack use dual-stack (IPv4 and IPv6) endpoints
+	DualStack bool
+}
+
 // SecretsProvider AWS secrets provider
 type SecretsProvider struct {
-	session *session.Session
-	sm      secretsmanageriface.SecretsManagerAPI
-	ssm     ssmiface.SSMAPI
+	cfg         *aws.Config
+	sm          SecretsManagerAPI
+	ssm         SSMAPI
+	smEndpoint  string
+	ssmEndpoint string
+	roles       map[string]string
+	clientsMu   sync.Mutex
+	clients     map[string]*clients
+	kmsMu       sync.Mutex
+	kms         map[string]KMSAPI
 }
 
 // NewAwsSecretsProvider init AWS Secrets Provider
-func NewAwsSecretsProvider() (secrets.Provider, error) {
-	var err error
-	sp := SecretsProvider{}
-	// create AWS session
-	sp.session, err = session.NewSessionWithOptions(session.Options{SharedConfigState: session.SharedConfigEnable})
+func NewAwsSecretsProvider(ctx context.Context, opts Options) (secrets.Provider, error) {
+	return newSecretsProvider(ctx, opts)
+}
+
+// newSecretsProvider init AWS Secrets Provider with additional AWS SDK config options
+func newSecretsProvider(ctx context.Context, opts Options, optFns ...func(*config.LoadOptions) error) (*SecretsProvider, error) {
+	sp := SecretsProvider{
+		roles:       opts.AssumeRoles,
+		smEndpoint:  opts.SecretsManagerEndpoint,
+		ssmEndpoint: opts.SSMEndpoint,
+	}
+	if opts.FIPS {
+		optFns = append(optFns, config.WithUseFIPSEndpoint(aws.FIPSEndpointStateEnabled))
+	}
+	if opts.DualStack {
+		optFns = append(optFns, config.WithUseDualStackEndpoint(aws.DualStackEndpointStateEnabled))
+	}
+	// load AWS SDK config: environment, shared config and credentials files, SSO, web identity, Pod Identity, IMDS
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create AWS session")
+		return nil, errors.Wrap(err, "failed to load AWS config")
 	}
-	// init AWS Secrets Manager client
-	sp.sm = secretsmanager.New(sp.session)
-	// init AWS SSM client
-	sp.ssm = ssm.New(sp.session)
+	// trace HTTP client; the SDK may customize the default transport (e.g. AWS_CA_BUNDLE)
+	if client, ok := cfg.HTTPClient.(*awshttp.BuildableClient); ok {
+		cfg.HTTPClient = &http.Client{Transport: otelhttp.NewTransport(client.GetTransport()), Timeout: client.GetTimeout()}
+	}
+	sp.cfg = &cfg
+	sp.sm, sp.ssm = sp.newClients(cfg)
 	return &sp, nil
 }
 
-// ResolveSecrets replaces all passed variables values prefixed with 'aws:aws:secretsmanager' and 'arn:aws:ssm:REGION:ACCOUNT:parameter'
-// by corresponding secrets from AWS Secret Manager and AWS Parameter Store
-func (sp *SecretsProvider) ResolveSecrets(_ context.Context, vars []string) ([]string, error) {
-	envs := make([]string, 0, len(vars))
+// ResolveSecrets replaces all passed variables values prefixed with 'arn:aws:secretsmanager', 'aws:secretsmanager:'
+// ('aws:sm:'), 'arn:aws:ssm:REGION:ACCOUNT:parameter' (in any AWS partition) and 'aws:ssm:' by corresponding secrets
+// from AWS Secret Manager and AWS Parameter Store. Secrets Manager references may select
+// the version with '?stage=VERSION_STAGE' or '?version=VERSION_ID'; a field of JSON secrets and parameters is
+// selected with '#FIELD'. Values prefixed with 'awskms:' are
+// base64 encoded AWS KMS ciphertexts replaced by the decrypted plaintext.
+// Secrets and parameters are read in the region of the ARN, assuming the role mapped to the ARN account if any
+func (sp *SecretsProvider) ResolveSecrets(ctx context.Context, vars []string) ([]string, error) {
+	resolved, err := sp.ResolveVariables(ctx, vars)
+	if err != nil {
+		return vars, err
+	}
+	envs := secrets.Flatten(resolved)
+	sort.Strings(envs)
+	return envs, nil
+}
 
-	for _, env := range vars {
-		kv := strings.Split(env, "=")
-		key, value := kv[0], kv[1]
-		if strings.HasPrefix(value, "arn:aws:secretsmanager") || strings.HasPrefix(value, "arn:aws-cn:secretsmanager") {
-			// get secret value
-			secret, err := sp.sm.GetSecretValue(&secretsmanager.GetSecretValueInput{SecretId: &value})
-			if err != nil {
-				return vars, errors.Wrap(err, "failed to get secret from AWS Secrets Manager")
-			}
-			if IsJSON(secret.SecretString) {
-				var keyValueSecret map[string]string
-				err = json.Unmarshal([]byte(*secret.SecretString), &keyValueSecret)
-				if err != nil {
-					return vars, errors.Wrap(err, "failed to decode key/value secret")
-				}
-				for key, value := range keyValueSecret {
-					e := key + "=" + value
-					envs = append(envs, e)
-				}
-				continue // We continue to not add this ENV variable but only the environment variables that exists in the JSON
-			}
-			env = key + "=" + *secret.SecretString
-		} else if (strings.HasPrefix(value, "arn:aws:ssm") || strings.HasPrefix(value, "arn:aws-cn:ssm")) && strings.Contains(value, ":parameter/") {
-			tokens := strings.Split(value, ":")
-			// valid parameter ARN arn:aws:ssm:REGION:ACCOUNT:parameter/PATH
-			// or arn:aws:ssm:REGION:ACCOUNT:parameter/PATH:VERSION
-			if len(tokens) == paramNameTokens || len(tokens) == paramNameTokensWithVersion {
-				// get SSM parameter name (path)
-				paramName := strings.TrimPrefix(tokens[5], "parameter")
-
-				if len(tokens) == paramNameTokensWithVersion {
-					paramName = paramName + ":" + tokens[6]
-				}
-
-				// get AWS SSM API
-				withDecryption := true
-				param, err := sp.ssm.GetParameter(&ssm.GetParameterInput{
-					Name:           &paramName,
-					WithDecryption: &withDecryption,
-				})
-				if err != nil {
-					return vars, errors.Wrap(err, "failed to get secret from AWS Parameters Store")
-				}
-				env = key + "=" + *param.Parameter.Value
-			}
+// ResolveVariables resolves secrets as ResolveSecrets, returning the environment variables resolved from every variable
+func (sp *SecretsProvider) ResolveVariables(ctx context.Context, vars []string) ([][]string, error) {
+	ctx, span := tracing.Start(ctx, "aws.ResolveSecrets", tracing.ProviderKey.String("aws"), tracing.RegionKey.String(sp.region()))
+	resolved, err := sp.resolveVariables(ctx, vars)
+	tracing.End(span, err)
+	return resolved, err
+}
+
+func (sp *SecretsProvider) resolveVariables(ctx context.Context, vars []string) ([][]string, error) {
+	resolved := make([][]string, len(vars))
+	var errs []*secrets.ResolveError
+
+	for i, env := range vars {
+		kv := strings.SplitN(env, "=", 2) //nolint:gomnd
+		envs, err := sp.resolveSecret(ctx, kv[0], kv[1])
+		if err != nil {
+			errs = append(errs, &secrets.ResolveError{Key: kv[0], Ref: kv[1], Err: err})
+			continue
 		}
-		envs = append(envs, env)
+		resolved[i] = envs
+	}
+	if len(errs) > 0 {
+		return nil, secrets.NewResolveErrors(errs)
+	}
+	return resolved, nil
+}
+
+// Cacheable reports whether the resolution of the variable can be cached: binary secrets written to files
+// ('?binary=file') are fetched every time, as the files do not survive a container restart
+func (sp *SecretsProvider) Cacheable(env string) bool {
+	_, value, _ := strings.Cut(env, "=")
+	if !isSecretsManagerRef(value) {
+		return true
+	}
+	ref, err := parseSecretRef(value)
+	return err != nil || ref.binary != binaryFile
+}
+
+// isSecretsManagerRef reports whether value is an AWS Secrets Manager reference
+func isSecretsManagerRef(value string) bool {
+	return secretsManagerARNRe.MatchString(value) || strings.HasPrefix(value, secretsManagerPrefix) || strings.HasPrefix(value, smPrefix)
+}
+
+// resolveSecret resolves single environment variable; key/value secrets and parameters paths are replaced by
+// a variable per key or parameter
+func (sp *SecretsProvider) resolveSecret(ctx context.Context, key, value string) ([]string, error) {
+	if strings.HasPrefix(value, kmsPrefix) {
+		plaintext, err := sp.decrypt(ctx, strings.TrimPrefix(value, kmsPrefix))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decrypt AWS KMS ciphertext")
+		}
+		return []string{key + "=" + string(plaintext)}, nil
+	}
+	if isSecretsManagerRef(value) {
+		return sp.resolveSecretsManager(ctx, key, value)
+	}
+	if ref, ok := parseParameterRef(value); ok {
+		return sp.resolveParameter(ctx, key, ref)
+	}
+	return []string{key + "=" + value}, nil
+}
+
+// resolveSecretsManager resolves Secrets Manager secret reference
+func (sp *SecretsProvider) resolveSecretsManager(ctx context.Context, key, value string) ([]string, error) {
+	ref, err := parseSecretRef(value)
+	if err != nil {
+		return nil, err
+	}
+	// get secret value
+	region, account := arnScope(ref.id)
+	if ref.region != "" {
+		region = ref.region
+	}
+	secret, err := sp.getSecretValue(ctx, sp.clientsFor(region, account).sm, ref)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get secret from AWS Secrets Manager")
+	}
+	switch {
+	case ref.field != "":
+		return selectField(key, ref.id, ref.field, secret.SecretString)
+	case IsJSON(secret.SecretString):
+		return keyValueVariables(*secret.SecretString)
+	case secret.SecretString == nil:
+		return binaryVariable(key, ref, secret.SecretBinary)
+	}
+	return []string{key + "=" + *secret.SecretString}, nil
+}
+
+// resolveParameter resolves SSM parameter reference; a parameters path is replaced by a variable per parameter
+func (sp *SecretsProvider) resolveParameter(ctx context.Context, key string, ref parameterRef) ([]string, error) {
+	client := sp.clientsFor(ref.region, ref.account).ssm
+	// load all parameters under the path: `/PATH/*`
+	if ref.selector == "" && strings.HasSuffix(ref.name, pathWildcard) {
+		if ref.field != "" {
+			return nil, errors.New("field can not be selected from parameters path")
+		}
+		params, err := sp.getParametersByPath(ctx, client, strings.TrimSuffix(ref.name, pathWildcard))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get secrets from AWS Parameters Store path")
+		}
+		return params, nil // the path variable is replaced by the parameters variables
+	}
+
+	// get AWS SSM API
+	param, err := sp.getParameter(ctx, client, ref.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get secret from AWS Parameters Store")
+	}
+	if ref.field != "" {
+		return selectField(key, ref.name, ref.field, param.Parameter.Value)
+	}
+	return []string{key + "=" + *param.Parameter.Value}, nil
+}
+
+// keyValueVariables returns a variable per key of JSON key/value secret
+func keyValueVariables(secretString string) ([]string, error) {
+	var keyValueSecret map[string]string
+	if err := json.Unmarshal([]byte(secretString), &keyValueSecret); err != nil {
+		return nil, errors.Wrap(err, "failed to decode key/value secret")
+	}
+	// only the environment variables that exists in the JSON are added
+	envs := make([]string, 0, len(keyValueSecret))
+	for key, value := range keyValueSecret {
+		envs = append(envs, key+"="+value)
 	}
-	sort.Strings(envs)
 	return envs, nil
 }
 
+// binaryVariable returns environment variable with the encoded binary secret value or the path of the file
+// the value is written to
+func binaryVariable(key string, ref secretRef, data []byte) ([]string, error) {
+	if data == nil {
+		return nil, errors.Errorf("secret %s has neither string nor binary value", ref.id)
+	}
+	binary, err := binaryValue(ref, data)
+	if err != nil {
+		return nil, err
+	}
+	return []string{key + "=" + binary}, nil
+}
+
+// parameterRef SSM parameter reference (ARN or short form with parameter name in the config region) with optional
+// version or label selector and JSON field
+//
+//	`arn:aws:ssm:REGION:ACCOUNT:parameter/PATH`
+//	`arn:aws:ssm:REGION:ACCOUNT:parameter/PATH:VERSION|LABEL`
+//	`aws:ssm:/PATH`
+//	`aws:ssm:/PATH:VERSION|LABEL`
+//	`aws:ssm:/PATH#FIELD`
+type parameterRef struct {
+	name     string
+	selector string
+	region   string
+	account  string
+	field    string
+}
+
+// parseParameterRef parses SSM parameter reference: `ID[#FIELD]`; not ok if value is not a valid parameter reference
+func parseParameterRef(value string) (ref parameterRef, ok bool) {
+	// parameter names can not contain '#'
+	value, field, hasField := strings.Cut(value, "#")
+	if hasField && field == "" {
+		return ref, false
+	}
+	ref.field = field
+	var name string
+	switch {
+	case strings.HasPrefix(value, ssmPrefix):
+		name = strings.TrimPrefix(value, ssmPrefix)
+	case ssmARNRe.MatchString(value):
+		a, err := arn.Parse(value)
+		if err != nil || !strings.HasPrefix(a.Resource, "parameter/") {
+			return ref, false
+		}
+		ref.region, ref.account = a.Region, a.AccountID
+		name = strings.TrimPrefix(a.Resource, "parameter")
+	default:
+		return ref, false
+	}
+	// parameter names can not contain ':'
+	tokens := strings.Split(name, ":")
+	if tokens[0] == "" || len(tokens) > 2 || (len(tokens) == 2 && tokens[1] == "") {
+		return ref, false
+	}
+	ref.name = tokens[0]
+	if len(tokens) == 2 {
+		ref.selector = tokens[1]
+	}
+	return ref, true
+}
+
+// String returns parameter name with selector, as expected by GetParameter
+func (ref parameterRef) String() string {
+	if ref.selector == "" {
+		return ref.name
+	}
+	return ref.name + ":" + ref.selector
+}
+
+// secretRef Secrets Manager secret reference (ARN or short form with secret name or partial ARN) with optional
+// region (short form), version stage or version ID and binary secret encoding ('base64' by default or 'file',
+// written to path or a temporary file)
+//
+//	`arn:aws:secretsmanager:REGION:ACCOUNT:secret:NAME`
+//	`aws:secretsmanager:NAME`
+//	`aws:sm:NAME?region=eu-west-1`
+//	`arn:aws:secretsmanager:REGION:ACCOUNT:secret:NAME?stage=AWSPREVIOUS`
+//	`arn:aws:secretsmanager:REGION:ACCOUNT:secret:NAME?version=VERSION_ID`
+//	`arn:aws:secretsmanager:REGION:ACCOUNT:secret:NAME?binary=file&path=/run/secrets/keystore.jks`
+type secretRef struct {
+	id      string
+	region  string
+	stage   string
+	version string
+	binary  string
+	path    string
+	field   string
+}
+
+// parseSecretRef parses Secrets Manager secret reference: `ID[?OPTIONS][#FIELD]`
+func parseSecretRef(value string) (secretRef, error) {
+	value = strings.TrimPrefix(strings.TrimPrefix(value, secretsManagerPrefix), smPrefix)
+	value, field, hasField := strings.Cut(value, "#")
+	id, rawQuery, _ := strings.Cut(value, "?")
+	ref := secretRef{id: id, field: field}
+	if hasField && field == "" {
+		return ref, errors.New("missing field after '#' in secret reference")
+	}
+	opts, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return ref, errors.Wrapf(err, "invalid secret reference options %q", rawQuery)
+	}
+	for opt := range opts {
+		if err = ref.setOption(opt, opts.Get(opt)); err != nil {
+			return ref, err
+		}
+	}
+	if ref.path != "" && ref.binary != binaryFile {
+		return ref, errors.New("secret reference option 'path' requires 'binary=file'")
+	}
+	if ref.field != "" && ref.binary != "" {
+		return ref, errors.New("secret reference field can not be selected from binary secret")
+	}
+	return ref, nil
+}
+
+// setOption sets secret reference option
+func (ref *secretRef) setOption(opt, value string) error {
+	switch opt {
+	case "region":
+		ref.region = value
+	case "stage":
+		ref.stage = value
+	case "version":
+		ref.version = value
+	case "binary":
+		if value != binaryBase64 && value != binaryFile {
+			return errors.Errorf("unsupported binary secret encoding %q", value)
+		}
+		ref.binary = value
+	case "path":
+		ref.path = value
+	default:
+		return errors.Errorf("unsupported secret reference option %q", opt)
+	}
+	return nil
+}
+
+// selectField returns environment variable with the value at the dot-separated field path of JSON secret
+// or parameter
+func selectField(key, name, field string, value *string) ([]string, error) {
+	if value == nil {
+		return nil, errors.Errorf("secret %s is not a JSON string secret", name)
+	}
+	var doc interface{}
+	decoder := json.NewDecoder(strings.NewReader(*value))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode JSON secret %s", name)
+	}
+	selected, err := secrets.ExtractField(doc, field)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select field from secret %s", name)
+	}
+	return []string{key + "=" + selected}, nil
+}
+
+// binaryValue encodes binary secret value as base64 or writes it to a file and returns the file path
+func binaryValue(ref secretRef, data []byte) (string, error) {
+	if ref.binary != binaryFile {
+		return base64.StdEncoding.EncodeToString(data), nil
+	}
+	var f *os.File
+	var err error
+	if ref.path != "" {
+		f, err = os.OpenFile(ref.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600) //nolint:gomnd
+	} else {
+		f, err = os.CreateTemp("", "secrets-init-*")
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create binary secret file")
+	}
+	if _, err = f.Write(data); err != nil {
+		_ = f.Close()
+		return "", errors.Wrapf(err, "failed to write binary secret file %s", f.Name())
+	}
+	if err = f.Close(); err != nil {
+		return "", errors.Wrapf(err, "failed to write binary secret file %s", f.Name())
+	}
+	return f.Name(), nil
+}
+
+// getSecretValue gets secret version from AWS Secrets Manager; AWSCURRENT unless stage or version is set
+func (sp *SecretsProvider) getSecretValue(ctx context.Context, sm SecretsManagerAPI, ref secretRef) (_ *secretsmanager.GetSecretValueOutput, err error) {
+	ctx, span := tracing.Start(ctx, "secretsmanager.GetSecretValue", tracing.SecretName(ref.id)...)
+	defer func() { tracing.End(span, err) }()
+	input := &secretsmanager.GetSecretValueInput{SecretId: aws.String(ref.id)}
+	if ref.stage != "" {
+		input.VersionStage = aws.String(ref.stage)
+	}
+	if ref.version != "" {
+		input.VersionId = aws.String(ref.version)
+	}
+	return sm.GetSecretValue(ctx, input) //nolint:wrapcheck
+}
+
+// getParameter gets decrypted parameter from AWS Parameter Store
+func (sp *SecretsProvider) getParameter(ctx context.Context, client SSMAPI, name string) (_ *ssm.GetParameterOutput, err error) {
+	ctx, span := tracing.Start(ctx, "ssm.GetParameter", tracing.SecretName(name)...)
+	defer func() { tracing.End(span, err) }()
+	return client.GetParameter(ctx, &ssm.GetParameterInput{ //nolint:wrapcheck
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(true),
+	})
+}
+
+// decrypt decrypts base64 encoded AWS KMS ciphertext; the key is identified by the ciphertext metadata
+func (sp *SecretsProvider) decrypt(ctx context.Context, encoded string) (_ []byte, err error) {
+	ctx, span := tracing.Start(ctx, "kms.Decrypt")
+	defer func() { tracing.End(span, err) }()
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid base64 ciphertext")
+	}
+	out, err := sp.KMS("").Decrypt(ctx, &kms.DecryptInput{CiphertextBlob: ciphertext})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	return out.Plaintext, nil
+}
+
+// getParametersByPath gets all decrypted parameters under path (recursively) from AWS Parameter Store as
+// environment variables named after the parameter name relative to path, e.g. `/app/prod/db/password-main`
+// under `/app/prod` is `DB_PASSWORD_MAIN`
+func (sp *SecretsProvider) getParametersByPath(ctx context.Context, client SSMAPI, path string) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "ssm.GetParametersByPath", tracing.SecretName(path)...)
+	defer func() { tracing.End(span, err) }()
+	if path == "" {
+		path = "/"
+	}
+	input := &ssm.GetParametersByPathInput{
+		Path:           aws.String(path),
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(true),
+	}
+	var envs []string
+	for {
+		var out *ssm.GetParametersByPathOutput
+		if out, err = client.GetParametersByPath(ctx, input); err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+		for _, param := range out.Parameters {
+			name := envName(strings.TrimPrefix(aws.ToString(param.Name), path))
+			if name == "" {
+				continue
+			}
+			envs = append(envs, name+"="+aws.ToString(param.Value))
+		}
+		if aws.ToString(out.NextToken) == "" {
+			return envs, nil
+		}
+		input.NextToken = out.NextToken
+	}
+}
+
+// envName converts relative parameter name to environment variable name: upper case, with '_' separators
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.Trim(name, "/"))
+}
+
+// KMS returns AWS KMS client for region (config region if empty) sharing the provider config
+func (sp *SecretsProvider) KMS(region string) KMSAPI {
+	sp.kmsMu.Lock()
+	defer sp.kmsMu.Unlock()
+	if region == "" {
+		region = sp.region()
+	}
+	if client, ok := sp.kms[region]; ok {
+		return client
+	}
+	if sp.kms == nil {
+		sp.kms = make(map[string]KMSAPI)
+	}
+	client := kms.NewFromConfig(*sp.cfg, func(o *kms.Options) { o.Region = region })
+	sp.kms[region] = client
+	return client
+}
+
+// region returns the AWS config region
+func (sp *SecretsProvider) region() string {
+	if sp.cfg == nil {
+		return ""
+	}
+	return sp.cfg.Region
+}
+
 func IsJSON(str *string) bool {
 	if str == nil {
 		return false
@@ -4,14 +4,23 @@ package aws
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"secrets-init/mocks"
 	"secrets-init/pkg/secrets"
 
-	"github.com/aws/aws-sdk-go/service/secretsmanager"
-	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSecretsProvider_ResolveSecrets(t *testing.T) {
@@ -36,7 +45,7 @@ func TestSecretsProvider_ResolveSecrets(t *testing.T) {
 				secretValue := "test-secret-value"
 				valueInput := secretsmanager.GetSecretValueInput{SecretId: &secretName}
 				valueOutput := secretsmanager.GetSecretValueOutput{SecretString: &secretValue}
-				mockSM.On("GetSecretValue", &valueInput).Return(&valueOutput, nil)
+				mockSM.On("GetSecretValue", mock.Anything, &valueInput).Return(&valueOutput, nil)
 				return &sp
 			},
 		},
@@ -63,7 +72,7 @@ func TestSecretsProvider_ResolveSecrets(t *testing.T) {
 					value := v
 					valueInput := secretsmanager.GetSecretValueInput{SecretId: &name}
 					valueOutput := secretsmanager.GetSecretValueOutput{SecretString: &value}
-					mockSM.On("GetSecretValue", &valueInput).Return(&valueOutput, nil)
+					mockSM.On("GetSecretValue", mock.Anything, &valueInput).Return(&valueOutput, nil)
 				}
 				return &sp
 			},
@@ -87,11 +96,65 @@ func TestSecretsProvider_ResolveSecrets(t *testing.T) {
 					value := v
 					valueInput := secretsmanager.GetSecretValueInput{SecretId: &name}
 					valueOutput := secretsmanager.GetSecretValueOutput{SecretString: &value}
-					mockSM.On("GetSecretValue", &valueInput).Return(&valueOutput, nil)
+					mockSM.On("GetSecretValue", mock.Anything, &valueInput).Return(&valueOutput, nil)
 				}
 				return &sp
 			},
 		},
+		{
+			name: "select fields of JSON secret from Secrets Manager",
+			vars: []string{
+				"test-password=arn:aws:secretsmanager:12345678-json#db.password",
+				"test-port=arn:aws:secretsmanager:12345678-json?stage=AWSPREVIOUS#db.port",
+				"test-host=arn:aws:secretsmanager:12345678-json#db.hosts.1",
+			},
+			want: []string{
+				"test-host=db-2",
+				"test-password=test-secret-value",
+				"test-port=5432",
+			},
+			mockServiceProvider: func(mockSM *mocks.SecretsManagerAPI, mockSSM *mocks.SSMAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, ssm: mockSSM}
+				name := "arn:aws:secretsmanager:12345678-json"
+				stage := "AWSPREVIOUS"
+				value := `{"db": {"password": "test-secret-value", "port": 5432, "hosts": ["db-1", "db-2"]}}`
+				valueOutput := secretsmanager.GetSecretValueOutput{SecretString: &value}
+				mockSM.On("GetSecretValue", mock.Anything, &secretsmanager.GetSecretValueInput{SecretId: &name}).Return(&valueOutput, nil).Twice()
+				mockSM.On("GetSecretValue", mock.Anything, &secretsmanager.GetSecretValueInput{SecretId: &name, VersionStage: &stage}).Return(&valueOutput, nil)
+				return &sp
+			},
+		},
+		{
+			name: "error missing field of JSON secret from Secrets Manager",
+			vars: []string{
+				"test-secret=arn:aws:secretsmanager:12345678-json#db.user",
+			},
+			want: []string{
+				"test-secret=arn:aws:secretsmanager:12345678-json#db.user",
+			},
+			wantErr: true,
+			mockServiceProvider: func(mockSM *mocks.SecretsManagerAPI, mockSSM *mocks.SSMAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, ssm: mockSSM}
+				name := "arn:aws:secretsmanager:12345678-json"
+				value := `{"db": {"password": "test-secret-value"}}`
+				valueOutput := secretsmanager.GetSecretValueOutput{SecretString: &value}
+				mockSM.On("GetSecretValue", mock.Anything, &secretsmanager.GetSecretValueInput{SecretId: &name}).Return(&valueOutput, nil)
+				return &sp
+			},
+		},
+		{
+			name: "error empty field of Secrets Manager secret reference",
+			vars: []string{
+				"test-secret=arn:aws:secretsmanager:12345678-json#",
+			},
+			want: []string{
+				"test-secret=arn:aws:secretsmanager:12345678-json#",
+			},
+			wantErr: true,
+			mockServiceProvider: func(mockSM *mocks.SecretsManagerAPI, mockSSM *mocks.SSMAPI) secrets.Provider {
+				return &SecretsProvider{sm: mockSM, ssm: mockSSM}
+			},
+		},
 		{
 			name: "no secrets",
 			vars: []string{
@@ -121,7 +184,155 @@ func TestSecretsProvider_ResolveSecrets(t *testing.T) {
 				sp := SecretsProvider{sm: mockSM, ssm: mockSSM}
 				secretName := "arn:aws:secretsmanager:12345678"
 				valueInput := secretsmanager.GetSecretValueInput{SecretId: &secretName}
-				mockSM.On("GetSecretValue", &valueInput).Return(nil, errors.New("test error"))
+				mockSM.On("GetSecretValue", mock.Anything, &valueInput).Return(nil, errors.New("test error"))
+				return &sp
+			},
+		},
+		{
+			name: "get secret version stage from Secrets Manager",
+			vars: []string{
+				"test-secret=arn:aws:secretsmanager:12345678?stage=AWSPREVIOUS",
+			},
+			want: []string{
+				"test-secret=test-secret-previous-value",
+			},
+			mockServiceProvider: func(mockSM *mocks.SecretsManagerAPI, mockSSM *mocks.SSMAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, ssm: mockSSM}
+				secretName := "arn:aws:secretsmanager:12345678"
+				secretStage := "AWSPREVIOUS"
+				secretValue := "test-secret-previous-value"
+				valueInput := secretsmanager.GetSecretValueInput{SecretId: &secretName, VersionStage: &secretStage}
+				valueOutput := secretsmanager.GetSecretValueOutput{SecretString: &secretValue}
+				mockSM.On("GetSecretValue", mock.Anything, &valueInput).Return(&valueOutput, nil)
+				return &sp
+			},
+		},
+		{
+			name: "get secret version ID from Secrets Manager",
+			vars: []string{
+				"test-secret=arn:aws:secretsmanager:12345678?version=a1b2c3d4-5678-90ab-cdef-EXAMPLE11111",
+			},
+			want: []string{
+				"test-secret=test-secret-version-value",
+			},
+			mockServiceProvider: func(mockSM *mocks.SecretsManagerAPI, mockSSM *mocks.SSMAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, ssm: mockSSM}
+				secretName := "arn:aws:secretsmanager:12345678"
+				secretVersion := "a1b2c3d4-5678-90ab-cdef-EXAMPLE11111"
+				secretValue := "test-secret-version-value"
+				valueInput := secretsmanager.GetSecretValueInput{SecretId: &secretName, VersionId: &secretVersion}
+				valueOutput := secretsmanager.GetSecretValueOutput{SecretString: &secretValue}
+				mockSM.On("GetSecretValue", mock.Anything, &valueInput).Return(&valueOutput, nil)
+				return &sp
+			},
+		},
+		{
+			name: "error unsupported Secrets Manager reference option",
+			vars: []string{
+				"test-secret=arn:aws:secretsmanager:12345678?label=AWSPREVIOUS",
+			},
+			want: []string{
+				"test-secret=arn:aws:secretsmanager:12345678?label=AWSPREVIOUS",
+			},
+			wantErr: true,
+			mockServiceProvider: func(mockSM *mocks.SecretsManagerAPI, mockSSM *mocks.SSMAPI) secrets.Provider {
+				return &SecretsProvider{sm: mockSM, ssm: mockSSM}
+			},
+		},
+		{
+			name: "get binary secret from Secrets Manager",
+			vars: []string{
+				"test-secret=arn:aws:secretsmanager:12345678",
+			},
+			want: []string{
+				"test-secret=AAECAw==",
+			},
+			mockServiceProvider: func(mockSM *mocks.SecretsManagerAPI, mockSSM *mocks.SSMAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, ssm: mockSSM}
+				secretName := "arn:aws:secretsmanager:12345678"
+				valueInput := secretsmanager.GetSecretValueInput{SecretId: &secretName}
+				valueOutput := secretsmanager.GetSecretValueOutput{SecretBinary: []byte{0, 1, 2, 3}}
+				mockSM.On("GetSecretValue", mock.Anything, &valueInput).Return(&valueOutput, nil)
+				return &sp
+			},
+		},
+		{
+			name: "error secret without value from Secrets Manager",
+			vars: []string{
+				"test-secret=arn:aws:secretsmanager:12345678",
+			},
+			want: []string{
+				"test-secret=arn:aws:secretsmanager:12345678",
+			},
+			wantErr: true,
+			mockServiceProvider: func(mockSM *mocks.SecretsManagerAPI, mockSSM *mocks.SSMAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, ssm: mockSSM}
+				secretName := "arn:aws:secretsmanager:12345678"
+				valueInput := secretsmanager.GetSecretValueInput{SecretId: &secretName}
+				mockSM.On("GetSecretValue", mock.Anything, &valueInput).Return(&secretsmanager.GetSecretValueOutput{}, nil)
+				return &sp
+			},
+		},
+		{
+			name: "error unsupported binary secret encoding",
+			vars: []string{
+				"test-secret=arn:aws:secretsmanager:12345678?binary=hex",
+			},
+			want: []string{
+				"test-secret=arn:aws:secretsmanager:12345678?binary=hex",
+			},
+			wantErr: true,
+			mockServiceProvider: func(mockSM *mocks.SecretsManagerAPI, mockSSM *mocks.SSMAPI) secrets.Provider {
+				return &SecretsProvider{sm: mockSM, ssm: mockSSM}
+			},
+		},
+		{
+			name: "get secrets from Secrets Manager by name and partial ARN",
+			vars: []string{
+				"test-secret-1=aws:secretsmanager:prod/db",
+				"test-secret-2=aws:sm:arn:aws:secretsmanager:us-east-1:123456789012:secret:prod/api",
+			},
+			want: []string{
+				"test-secret-1=test-secret-value-1",
+				"test-secret-2=test-secret-value-2",
+			},
+			mockServiceProvider: func(mockSM *mocks.SecretsManagerAPI, mockSSM *mocks.SSMAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, ssm: mockSSM}
+				vars := map[string]string{
+					"prod/db": "test-secret-value-1",
+					"arn:aws:secretsmanager:us-east-1:123456789012:secret:prod/api": "test-secret-value-2",
+				}
+				for n, v := range vars {
+					name := n
+					value := v
+					valueInput := secretsmanager.GetSecretValueInput{SecretId: &name}
+					valueOutput := secretsmanager.GetSecretValueOutput{SecretString: &value}
+					mockSM.On("GetSecretValue", mock.Anything, &valueInput).Return(&valueOutput, nil)
+				}
+				return &sp
+			},
+		},
+		{
+			name: "get secret and parameter from other AWS partitions",
+			vars: []string{
+				"test-secret=arn:aws-us-gov:secretsmanager:us-gov-west-1:123456789012:secret:db",
+				"test-param=arn:aws-iso-b:ssm:us-isob-east-1:123456789012:parameter/secrets/test-secret",
+			},
+			want: []string{
+				"test-param=test-param-value",
+				"test-secret=test-secret-value",
+			},
+			mockServiceProvider: func(mockSM *mocks.SecretsManagerAPI, mockSSM *mocks.SSMAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, ssm: mockSSM}
+				secretName := "arn:aws-us-gov:secretsmanager:us-gov-west-1:123456789012:secret:db"
+				secretValue := "test-secret-value"
+				mockSM.On("GetSecretValue", mock.Anything, &secretsmanager.GetSecretValueInput{SecretId: &secretName}).
+					Return(&secretsmanager.GetSecretValueOutput{SecretString: &secretValue}, nil)
+				paramName := "/secrets/test-secret"
+				paramValue := "test-param-value"
+				withDecryption := true
+				mockSSM.On("GetParameter", mock.Anything, &ssm.GetParameterInput{Name: &paramName, WithDecryption: &withDecryption}).
+					Return(&ssm.GetParameterOutput{Parameter: &types.Parameter{Value: &paramValue}}, nil)
 				return &sp
 			},
 		},
@@ -139,8 +350,107 @@ func TestSecretsProvider_ResolveSecrets(t *testing.T) {
 				secretValue := "test-secret-value"
 				withDecryption := true
 				valueInput := ssm.GetParameterInput{Name: &secretName, WithDecryption: &withDecryption}
-				valueOutput := ssm.GetParameterOutput{Parameter: &ssm.Parameter{Value: &secretValue}}
-				mockSSM.On("GetParameter", &valueInput).Return(&valueOutput, nil)
+				valueOutput := ssm.GetParameterOutput{Parameter: &types.Parameter{Value: &secretValue}}
+				mockSSM.On("GetParameter", mock.Anything, &valueInput).Return(&valueOutput, nil)
+				return &sp
+			},
+		},
+		{
+			name: "get all parameters under SSM Parameter Store path",
+			vars: []string{
+				"SSM_PATH=arn:aws:ssm:us-east-1:12345678:parameter/app/prod/*",
+				"non-secret=hello",
+			},
+			want: []string{
+				"API_KEY=key-123456789",
+				"DB_PASSWORD_MAIN=test-secret-value",
+				"non-secret=hello",
+			},
+			mockServiceProvider: func(mockSM *mocks.SecretsManagerAPI, mockSSM *mocks.SSMAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, ssm: mockSSM}
+				path := "/app/prod"
+				recursive := true
+				withDecryption := true
+				nextToken := "next-page"
+				input := ssm.GetParametersByPathInput{Path: &path, Recursive: &recursive, WithDecryption: &withDecryption}
+				mockSSM.On("GetParametersByPath", mock.Anything, &input).Return(&ssm.GetParametersByPathOutput{
+					Parameters: []types.Parameter{
+						{Name: aws.String("/app/prod/db/password-main"), Value: aws.String("test-secret-value")},
+					},
+					NextToken: &nextToken,
+				}, nil).Once()
+				nextInput := input
+				nextInput.NextToken = &nextToken
+				mockSSM.On("GetParametersByPath", mock.Anything, &nextInput).Return(&ssm.GetParametersByPathOutput{
+					Parameters: []types.Parameter{
+						{Name: aws.String("/app/prod/api-key"), Value: aws.String("key-123456789")},
+					},
+				}, nil).Once()
+				return &sp
+			},
+		},
+		{
+			name: "error getting parameters under SSM Parameter Store path",
+			vars: []string{
+				"SSM_PATH=arn:aws:ssm:us-east-1:12345678:parameter/app/prod/*",
+			},
+			want: []string{
+				"SSM_PATH=arn:aws:ssm:us-east-1:12345678:parameter/app/prod/*",
+			},
+			wantErr: true,
+			mockServiceProvider: func(mockSM *mocks.SecretsManagerAPI, mockSSM *mocks.SSMAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, ssm: mockSSM}
+				mockSSM.On("GetParametersByPath", mock.Anything, mock.Anything).Return(nil, errors.New("test error"))
+				return &sp
+			},
+		},
+		{
+			name: "get SSM Parameters by name with version and label",
+			vars: []string{
+				"test-secret-1=aws:ssm:/secrets/test-secret",
+				"test-secret-2=aws:ssm:/secrets/test-secret:3",
+				"test-secret-3=aws:ssm:/secrets/test-secret:prod",
+			},
+			want: []string{
+				"test-secret-1=test-secret-value-latest",
+				"test-secret-2=test-secret-value-3",
+				"test-secret-3=test-secret-value-prod",
+			},
+			mockServiceProvider: func(mockSM *mocks.SecretsManagerAPI, mockSSM *mocks.SSMAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, ssm: mockSSM}
+				vars := map[string]string{
+					"/secrets/test-secret":      "test-secret-value-latest",
+					"/secrets/test-secret:3":    "test-secret-value-3",
+					"/secrets/test-secret:prod": "test-secret-value-prod",
+				}
+				for n, v := range vars {
+					name := n
+					value := v
+					withDecryption := true
+					valueInput := ssm.GetParameterInput{Name: &name, WithDecryption: &withDecryption}
+					valueOutput := ssm.GetParameterOutput{Parameter: &types.Parameter{Value: &value}}
+					mockSSM.On("GetParameter", mock.Anything, &valueInput).Return(&valueOutput, nil)
+				}
+				return &sp
+			},
+		},
+		{
+			name: "select field of JSON SSM Parameter",
+			vars: []string{
+				"test-password=aws:ssm:/secrets/db-config#db.password",
+				"test-port=arn:aws:ssm:us-east-1:12345678:parameter/secrets/db-config#db.port",
+			},
+			want: []string{
+				"test-password=test-secret-value",
+				"test-port=5432",
+			},
+			mockServiceProvider: func(mockSM *mocks.SecretsManagerAPI, mockSSM *mocks.SSMAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, ssm: mockSSM}
+				paramName := "/secrets/db-config"
+				paramValue := `{"db": {"password": "test-secret-value", "port": 5432}}`
+				withDecryption := true
+				mockSSM.On("GetParameter", mock.Anything, &ssm.GetParameterInput{Name: &paramName, WithDecryption: &withDecryption}).
+					Return(&ssm.GetParameterOutput{Parameter: &types.Parameter{Value: &paramValue}}, nil)
 				return &sp
 			},
 		},
@@ -160,7 +470,7 @@ func TestSecretsProvider_ResolveSecrets(t *testing.T) {
 				secretName := "/secrets/test-secret"
 				withDecryption := true
 				valueInput := ssm.GetParameterInput{Name: &secretName, WithDecryption: &withDecryption}
-				mockSSM.On("GetParameter", &valueInput).Return(nil, errors.New("test error"))
+				mockSSM.On("GetParameter", mock.Anything, &valueInput).Return(nil, errors.New("test error"))
 				return &sp
 			},
 		},
@@ -183,3 +493,173 @@ func TestSecretsProvider_ResolveSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestSecretsProvider_ResolveSecrets_Errors(t *testing.T) {
+	mockSM := &mocks.SecretsManagerAPI{}
+	mockSSM := &mocks.SSMAPI{}
+	mockSM.On("GetSecretValue", mock.Anything, mock.Anything).Return(nil, errors.New("access denied"))
+	mockSSM.On("GetParameter", mock.Anything, &ssm.GetParameterInput{Name: aws.String("/secrets/missing"), WithDecryption: aws.Bool(true)}).
+		Return(nil, errors.New("parameter not found"))
+	mockSSM.On("GetParameter", mock.Anything, &ssm.GetParameterInput{Name: aws.String("/secrets/found"), WithDecryption: aws.Bool(true)}).
+		Return(&ssm.GetParameterOutput{Parameter: &types.Parameter{Value: aws.String("value")}}, nil)
+	sp := &SecretsProvider{sm: mockSM, ssm: mockSSM}
+
+	vars := []string{
+		"SM=arn:aws:secretsmanager:us-east-1:12345678:secret:test-secret",
+		"FOUND=aws:ssm:/secrets/found",
+		"MISSING=aws:ssm:/secrets/missing",
+		"INVALID=aws:sm:test-secret?unknown=1",
+		"non-secret=hello",
+	}
+	got, err := sp.ResolveSecrets(context.TODO(), vars)
+	assert.Equal(t, vars, got)
+	var errs secrets.ResolveErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 3)
+	assert.Equal(t, "INVALID", errs[0].Key)
+	assert.Equal(t, "aws:sm:test-secret?unknown=1", errs[0].Ref)
+	assert.Equal(t, "MISSING", errs[1].Key)
+	assert.Equal(t, "aws:ssm:/secrets/missing", errs[1].Ref)
+	assert.ErrorContains(t, errs[1], "parameter not found")
+	assert.Equal(t, "SM", errs[2].Key)
+	assert.ErrorContains(t, errs[2], "access denied")
+	assert.ErrorContains(t, err, "failed to resolve 3 secret(s)")
+	mockSM.AssertExpectations(t)
+	mockSSM.AssertExpectations(t)
+}
+
+func TestSecretsProvider_ResolveSecrets_KMS(t *testing.T) {
+	tests := []struct {
+		name    string
+		vars    []string
+		mockKMS func(*mocks.KMSAPI)
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "decrypt inline AWS KMS ciphertext",
+			vars: []string{
+				"test-secret=awskms:Y2lwaGVydGV4dA==",
+				"non-secret=hello",
+			},
+			want: []string{
+				"non-secret=hello",
+				"test-secret=test-secret-value",
+			},
+			mockKMS: func(mockKMS *mocks.KMSAPI) {
+				input := kms.DecryptInput{CiphertextBlob: []byte("ciphertext")}
+				mockKMS.On("Decrypt", mock.Anything, &input).Return(&kms.DecryptOutput{Plaintext: []byte("test-secret-value")}, nil)
+			},
+		},
+		{
+			name: "error invalid base64 ciphertext",
+			vars: []string{
+				"test-secret=awskms:not-base64!",
+			},
+			want: []string{
+				"test-secret=awskms:not-base64!",
+			},
+			mockKMS: func(mockKMS *mocks.KMSAPI) {},
+			wantErr: true,
+		},
+		{
+			name: "error decrypting AWS KMS ciphertext",
+			vars: []string{
+				"test-secret=awskms:Y2lwaGVydGV4dA==",
+			},
+			want: []string{
+				"test-secret=awskms:Y2lwaGVydGV4dA==",
+			},
+			mockKMS: func(mockKMS *mocks.KMSAPI) {
+				mockKMS.On("Decrypt", mock.Anything, mock.Anything).Return(nil, errors.New("test error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockKMS := mocks.NewKMSAPI(t)
+			tt.mockKMS(mockKMS)
+			sp := &SecretsProvider{kms: map[string]KMSAPI{"": mockKMS}}
+			got, err := sp.ResolveSecrets(context.TODO(), tt.vars)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SecretsProvider.ResolveSecrets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SecretsProvider.ResolveSecrets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretsProvider_ResolveSecrets_BinaryFile(t *testing.T) {
+	mockSM := mocks.NewSecretsManagerAPI(t)
+	secretName := "arn:aws:secretsmanager:12345678"
+	valueInput := secretsmanager.GetSecretValueInput{SecretId: &secretName}
+	valueOutput := secretsmanager.GetSecretValueOutput{SecretBinary: []byte{0, 1, 2, 3}}
+	mockSM.On("GetSecretValue", mock.Anything, &valueInput).Return(&valueOutput, nil)
+	sp := &SecretsProvider{sm: mockSM}
+
+	path := filepath.Join(t.TempDir(), "keystore.jks")
+	got, err := sp.ResolveSecrets(context.TODO(), []string{
+		"test-secret=" + secretName + "?binary=file&path=" + path,
+		"test-temp-secret=" + secretName + "?binary=file",
+	})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "test-secret="+path, got[0])
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 1, 2, 3}, data)
+
+	tempPath := strings.TrimPrefix(got[1], "test-temp-secret=")
+	t.Cleanup(func() { _ = os.Remove(tempPath) })
+	data, err = os.ReadFile(tempPath)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 1, 2, 3}, data)
+}
+
+func TestSecretsProvider_Cacheable(t *testing.T) {
+	sp := &SecretsProvider{}
+	assert.True(t, sp.Cacheable("test-secret=arn:aws:secretsmanager:us-east-1:123456789012:secret:test"))
+	assert.True(t, sp.Cacheable("test-secret=aws:sm:test?binary=base64"))
+	assert.True(t, sp.Cacheable("test-secret=aws:ssm:/test/param"))
+	assert.True(t, sp.Cacheable("non-secret=hello"))
+	// files written for binary secrets do not survive a restart
+	assert.False(t, sp.Cacheable("test-secret=aws:sm:test?binary=file"))
+	assert.False(t, sp.Cacheable("test-secret=aws:sm:test?binary=file&path=/run/secrets/keystore.jks"))
+}
+
+func TestParseParameterRef(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   parameterRef
+		wantOk bool
+	}{
+		{value: "arn:aws:ssm:us-east-1:123456789012:parameter/app/key", want: parameterRef{name: "/app/key", region: "us-east-1", account: "123456789012"}, wantOk: true},
+		{value: "arn:aws:ssm:us-east-1:123456789012:parameter/app/key:3", want: parameterRef{name: "/app/key", selector: "3", region: "us-east-1", account: "123456789012"}, wantOk: true},
+		{value: "arn:aws-cn:ssm:cn-north-1:123456789012:parameter/app/key:prod", want: parameterRef{name: "/app/key", selector: "prod", region: "cn-north-1", account: "123456789012"}, wantOk: true},
+		{value: "aws:ssm:/app/key", want: parameterRef{name: "/app/key"}, wantOk: true},
+		{value: "aws:ssm:/app/key:3", want: parameterRef{name: "/app/key", selector: "3"}, wantOk: true},
+		{value: "aws:ssm:/app/key:prod", want: parameterRef{name: "/app/key", selector: "prod"}, wantOk: true},
+		{value: "aws:ssm:/app/*", want: parameterRef{name: "/app/*"}, wantOk: true},
+		{value: "aws:ssm:/app/config#db.password", want: parameterRef{name: "/app/config", field: "db.password"}, wantOk: true},
+		{value: "arn:aws:ssm:us-east-1:123456789012:parameter/app/config:3#db.hosts.0", want: parameterRef{name: "/app/config", selector: "3", region: "us-east-1", account: "123456789012", field: "db.hosts.0"}, wantOk: true},
+		{value: "aws:ssm:/app/config#"},
+		{value: "arn:aws:ssm:us-east-1:123456789012:document/app"},
+		{value: "arn:aws:ssm:us-east-1:123456789012:parameter/app/key:3:4"},
+		{value: "aws:ssm:/app/key:"},
+		{value: "aws:ssm:"},
+		{value: "/app/key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseParameterRef(tt.value)
+			assert.Equal(t, tt.wantOk, ok)
+			if tt.wantOk {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"secrets-init/pkg/secrets" //nolint:gci
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SecretsProvider caching secrets provider; it wraps a cloud secrets provider and serves resolved
+// variables from the cache, resolving the variables missing from the cache at once
+type SecretsProvider struct {
+	provider secrets.VariablesProvider
+	store    Store
+	ttl      time.Duration
+}
+
+// Filter is implemented by wrapped providers resolving some variables to values that can not be served from
+// the cache, e.g. paths of files written by the provider that do not survive a container restart
+type Filter interface {
+	// Cacheable reports whether the resolution of the variable can be cached
+	Cacheable(env string) bool
+}
+
+// NewCachingSecretsProvider init caching provider wrapping provider
+func NewCachingSecretsProvider(provider secrets.VariablesProvider, store Store, ttl time.Duration) secrets.Provider {
+	return &SecretsProvider{provider: provider, store: store, ttl: ttl}
+}
+
+// ResolveSecrets resolves every passed variable from the cache, then resolves all variables missing from
+// the cache with a single call to the wrapped provider; variables changed by the wrapped provider are cached
+// with the variable as key. Cache failures are logged and never fail the resolution.
+func (sp *SecretsProvider) ResolveSecrets(ctx context.Context, vars []string) ([]string, error) {
+	resolved := make([][]string, len(vars))
+	misses := make([]string, 0, len(vars))
+	missing := make([]int, 0, len(vars))
+	for i, env := range vars {
+		cached, ok, err := sp.store.Get(env)
+		if err != nil {
+			log.WithError(err).Warn("failed to read secrets cache")
+		}
+		if ok {
+			resolved[i] = cached
+			continue
+		}
+		misses = append(misses, env)
+		missing = append(missing, i)
+	}
+	if len(misses) == 0 {
+		return secrets.Flatten(resolved), nil
+	}
+
+	fetched, err := sp.provider.ResolveVariables(ctx, misses)
+	if err != nil {
+		return vars, err //nolint:wrapcheck
+	}
+	expires := time.Now().Add(sp.ttl)
+	for j, envs := range fetched {
+		resolved[missing[j]] = envs
+		// cache resolved secrets only
+		if len(envs) == 1 && envs[0] == misses[j] || !sp.cacheable(misses[j]) {
+			continue
+		}
+		if err = sp.store.Put(misses[j], envs, expires); err != nil {
+			log.WithError(err).Warn("failed to write secrets cache")
+		}
+	}
+	return secrets.Flatten(resolved), nil
+}
+
+// cacheable reports whether the resolution of the variable can be cached by the wrapped provider
+func (sp *SecretsProvider) cacheable(env string) bool {
+	if f, ok := sp.provider.(Filter); ok {
+		return f.Cacheable(env)
+	}
+	return true
+}
@@ -0,0 +1,196 @@
+// nolint
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"secrets-init/pkg/secrets"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider resolves variables with "fake:" prefixed values, expands "fake-json:" prefixed values into
+// two variables, resolves "fake-file:" prefixed values to not cacheable file paths and counts calls and
+// resolved secrets
+type fakeProvider struct {
+	calls   int
+	fetched int
+	err     error
+}
+
+func (p *fakeProvider) ResolveSecrets(ctx context.Context, vars []string) ([]string, error) {
+	resolved, err := p.ResolveVariables(ctx, vars)
+	if err != nil {
+		return vars, err
+	}
+	return secrets.Flatten(resolved), nil
+}
+
+func (p *fakeProvider) ResolveVariables(_ context.Context, vars []string) ([][]string, error) {
+	p.calls++
+	resolved := make([][]string, len(vars))
+	var errs []*secrets.ResolveError
+	for i, env := range vars {
+		kv := strings.SplitN(env, "=", 2)
+		switch {
+		case p.err != nil && strings.HasPrefix(kv[1], "fake"):
+			errs = append(errs, &secrets.ResolveError{Key: kv[0], Ref: kv[1], Err: p.err})
+		case strings.HasPrefix(kv[1], "fake:"):
+			p.fetched++
+			resolved[i] = []string{kv[0] + "=" + strings.TrimPrefix(kv[1], "fake:") + "-value"}
+		case strings.HasPrefix(kv[1], "fake-json:"):
+			p.fetched++
+			name := strings.TrimPrefix(kv[1], "fake-json:")
+			resolved[i] = []string{name + "_USER=admin", name + "_PASSWORD=" + name + "-value"}
+		case strings.HasPrefix(kv[1], "fake-file:"):
+			p.fetched++
+			resolved[i] = []string{kv[0] + "=/tmp/" + strings.TrimPrefix(kv[1], "fake-file:")}
+		default:
+			resolved[i] = []string{env}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, secrets.NewResolveErrors(errs)
+	}
+	return resolved, nil
+}
+
+func (p *fakeProvider) Cacheable(env string) bool {
+	return !strings.Contains(env, "=fake-file:")
+}
+
+func TestSecretsProvider_ResolveSecrets(t *testing.T) {
+	vars := []string{
+		"test-secret=fake:test-secret",
+		"non-secret=hello",
+		"other-secret=fake:other-secret",
+		"json-secret=fake-json:DB",
+	}
+	want := []string{
+		"test-secret=test-secret-value",
+		"non-secret=hello",
+		"other-secret=other-secret-value",
+		"DB_USER=admin",
+		"DB_PASSWORD=DB-value",
+	}
+	tests := []struct {
+		name        string
+		ttl         time.Duration
+		wantFetched int
+	}{
+		{
+			name:        "second resolution is served from cache",
+			ttl:         time.Minute,
+			wantFetched: 3,
+		},
+		{
+			name:        "expired entries are fetched again",
+			ttl:         -time.Minute,
+			wantFetched: 6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewFileStore(t.TempDir(), []byte("test-data-key"))
+			require.NoError(t, err)
+			fake := &fakeProvider{}
+			sp := NewCachingSecretsProvider(fake, store, tt.ttl)
+
+			for i := 0; i < 2; i++ {
+				got, err := sp.ResolveSecrets(context.TODO(), vars)
+				assert.NoError(t, err)
+				assert.Equal(t, want, got)
+			}
+			assert.Equal(t, tt.wantFetched, fake.fetched)
+			// all variables missing from the cache are resolved at once
+			assert.Equal(t, 2, fake.calls)
+		})
+	}
+}
+
+func TestSecretsProvider_ResolveSecrets_AllCached(t *testing.T) {
+	store, err := NewFileStore(t.TempDir(), []byte("test-data-key"))
+	require.NoError(t, err)
+	fake := &fakeProvider{}
+	sp := NewCachingSecretsProvider(fake, store, time.Minute)
+	vars := []string{"test-secret=fake:test-secret"}
+
+	for i := 0; i < 2; i++ {
+		got, err := sp.ResolveSecrets(context.TODO(), vars)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"test-secret=test-secret-value"}, got)
+	}
+	// the provider is not called when every variable is cached
+	assert.Equal(t, 1, fake.calls)
+
+	// variables without secret reference are not cached
+	_, err = sp.ResolveSecrets(context.TODO(), []string{"non-secret=hello"})
+	assert.NoError(t, err)
+	_, ok, err := store.Get("non-secret=hello")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// variables the provider can not serve from the cache are fetched every time
+	for i := 0; i < 2; i++ {
+		got, err := sp.ResolveSecrets(context.TODO(), []string{"file-secret=fake-file:keystore"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"file-secret=/tmp/keystore"}, got)
+	}
+	assert.Equal(t, 4, fake.calls)
+	_, ok, err = store.Get("file-secret=fake-file:keystore")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestSecretsProvider_ResolveSecretsError(t *testing.T) {
+	store, err := NewFileStore(t.TempDir(), []byte("test-data-key"))
+	require.NoError(t, err)
+	vars := []string{"test-secret=fake:test-secret", "other-secret=fake:other-secret", "non-secret=hello"}
+	sp := NewCachingSecretsProvider(&fakeProvider{err: errors.New("test error")}, store, time.Minute)
+
+	got, err := sp.ResolveSecrets(context.TODO(), vars)
+	assert.Equal(t, vars, got)
+	// failures of every variable are reported
+	var errs secrets.ResolveErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 2)
+	assert.Equal(t, "other-secret", errs[0].Key)
+	assert.Equal(t, "fake:other-secret", errs[0].Ref)
+	assert.Equal(t, "test-secret", errs[1].Key)
+}
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir, []byte("test-data-key"))
+	require.NoError(t, err)
+	ref := "test-secret=arn:aws:secretsmanager:12345678"
+	require.NoError(t, store.Put(ref, []string{"test-secret=very-secret-password"}, time.Now().Add(time.Minute)))
+
+	// entries are encrypted and do not expose the reference
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.NotContains(t, files[0].Name(), "secretsmanager")
+	data, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "very-secret-password")
+
+	envs, ok, err := store.Get(ref)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"test-secret=very-secret-password"}, envs)
+
+	// entries can not be read with another key
+	other, err := NewFileStore(dir, []byte("other-data-key"))
+	require.NoError(t, err)
+	_, ok, err = other.Get(ref)
+	assert.Error(t, err)
+	assert.False(t, ok)
+}
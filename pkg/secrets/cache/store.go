@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Store cache storage for resolved secrets
+type Store interface {
+	// Get returns cached environment variables resolved from ref; ok is false if there is no valid entry
+	Get(ref string) (envs []string, ok bool, err error)
+	// Put caches environment variables resolved from ref until expiration
+	Put(ref string, envs []string, expires time.Time) error
+}
+
+type entry struct {
+	Ref     string    `json:"ref"`
+	Envs    []string  `json:"envs"`
+	Expires time.Time `json:"expires"`
+}
+
+// FileStore encrypted on-disk cache store; every entry is stored in a separate file
+// encrypted with AES-256-GCM
+type FileStore struct {
+	dir  string
+	aead cipher.AEAD
+}
+
+// NewFileStore init encrypted file store in dir; the SHA-256 digest of the data key is used as encryption key
+func NewFileStore(dir string, dataKey []byte) (*FileStore, error) {
+	if len(dataKey) == 0 {
+		return nil, errors.New("empty cache data key")
+	}
+	key := sha256.Sum256(dataKey)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cache cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cache cipher")
+	}
+	if err = os.MkdirAll(dir, 0o700); err != nil { //nolint:gomnd
+		return nil, errors.Wrap(err, "failed to create cache directory")
+	}
+	return &FileStore{dir: dir, aead: aead}, nil
+}
+
+// NewFileStoreWithKeyFile init encrypted file store in dir with data key read from keyFile
+func NewFileStoreWithKeyFile(dir, keyFile string) (*FileStore, error) {
+	dataKey, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read cache data key")
+	}
+	return NewFileStore(dir, dataKey)
+}
+
+// Get returns cached environment variables resolved from ref
+func (fs *FileStore) Get(ref string) ([]string, bool, error) {
+	name := fs.name(ref)
+	data, err := os.ReadFile(filepath.Join(fs.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to read cache entry")
+	}
+	nonceSize := fs.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, false, errors.New("corrupted cache entry")
+	}
+	plain, err := fs.aead.Open(nil, data[:nonceSize], data[nonceSize:], []byte(name))
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to decrypt cache entry")
+	}
+	var e entry
+	if err = json.Unmarshal(plain, &e); err != nil {
+		return nil, false, errors.Wrap(err, "failed to decode cache entry")
+	}
+	if e.Ref != ref || time.Now().After(e.Expires) {
+		return nil, false, nil
+	}
+	return e.Envs, true, nil
+}
+
+// Put caches environment variables resolved from ref until expiration
+func (fs *FileStore) Put(ref string, envs []string, expires time.Time) error {
+	plain, err := json.Marshal(entry{Ref: ref, Envs: envs, Expires: expires})
+	if err != nil {
+		return errors.Wrap(err, "failed to encode cache entry")
+	}
+	nonce := make([]byte, fs.aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return errors.Wrap(err, "failed to generate nonce")
+	}
+	name := fs.name(ref)
+	data := fs.aead.Seal(nonce, nonce, plain, []byte(name))
+	// write to a temporary file first, so a concurrent reader never sees a partial entry
+	tmp, err := os.CreateTemp(fs.dir, name+".*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create cache entry")
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, "failed to write cache entry")
+	}
+	if err = tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write cache entry")
+	}
+	return errors.Wrap(os.Rename(tmp.Name(), filepath.Join(fs.dir, name)), "failed to write cache entry")
+}
+
+// name returns the entry file name; the reference is hashed so it is not exposed in the file system
+func (fs *FileStore) name(ref string) string {
+	sum := sha256.Sum256([]byte(ref))
+	return hex.EncodeToString(sum[:])
+}
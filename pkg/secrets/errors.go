@@ -0,0 +1,74 @@
+package secrets
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResolveError failure to resolve the secret reference of an environment variable
+type ResolveError struct {
+	// Key environment variable name
+	Key string
+	// Ref secret reference (environment variable value)
+	Ref string
+	// Err cause
+	Err error
+}
+
+func (e *ResolveError) Error() string {
+	return fmt.Sprintf("%s=%s: %v", e.Key, e.Ref, e.Err)
+}
+
+// Unwrap returns the cause
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
+
+// ResolveErrors failures to resolve secret references, reported at once so that all broken references are
+// known; sorted by environment variable name
+type ResolveErrors []*ResolveError
+
+// NewResolveErrors returns errors sorted by environment variable name, or nil if there are none
+func NewResolveErrors(errs []*ResolveError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	sorted := make(ResolveErrors, len(errs))
+	copy(sorted, errs)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+	return sorted
+}
+
+func (e ResolveErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("failed to resolve %d secret(s): %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns every failure
+func (e ResolveErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// ProviderErrors failures of multiple providers of a chain
+type ProviderErrors []error
+
+func (e ProviderErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns every failure
+func (e ProviderErrors) Unwrap() []error {
+	return e
+}
@@ -0,0 +1,153 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"time"
+
+	"secrets-init/pkg/secrets" //nolint:gci
+
+	"github.com/pkg/errors" //nolint:gci
+)
+
+const (
+	execPrefix   = "exec:"
+	pluginPrefix = "plugin:"
+)
+
+// PluginRequest request written by the provider to the plugin stdin
+type PluginRequest struct {
+	References []string `json:"references"`
+}
+
+// PluginResult value or error for a single reference
+type PluginResult struct {
+	Value string `json:"value,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// PluginResponse response read by the provider from the plugin stdout
+type PluginResponse struct {
+	Results map[string]PluginResult `json:"results"`
+}
+
+// SecretsProvider external command secrets provider
+type SecretsProvider struct {
+	allowExec bool
+	plugin    string
+	timeout   time.Duration
+}
+
+// NewExecSecretsProvider init exec secrets provider; 'exec:' references are run only when allowExec is set,
+// plugin is the optional plugin binary resolving 'plugin:' references
+func NewExecSecretsProvider(allowExec bool, plugin string, timeout time.Duration) secrets.Provider {
+	return &SecretsProvider{allowExec: allowExec, plugin: plugin, timeout: timeout}
+}
+
+// ResolveSecrets replaces all passed variables values prefixed with 'exec:' by the output of the referenced
+// command (when allowed), and values prefixed with 'plugin:' by the values returned by the plugin
+//
+//	`exec:/usr/local/bin/fetch-secret db/password`
+//	`plugin:db/password`
+//
+// All 'plugin:' references are resolved with a single plugin call: the plugin reads a JSON PluginRequest
+// from stdin and writes a JSON PluginResponse to stdout
+func (sp *SecretsProvider) ResolveSecrets(ctx context.Context, vars []string) ([]string, error) {
+	envs := make([]string, 0, len(vars))
+
+	// resolve plugin references in one batch
+	var refs []string
+	for _, env := range vars {
+		_, value := split(env)
+		if strings.HasPrefix(value, pluginPrefix) {
+			refs = append(refs, strings.TrimPrefix(value, pluginPrefix))
+		}
+	}
+	var results map[string]PluginResult
+	if len(refs) > 0 {
+		var err error
+		results, err = sp.callPlugin(ctx, refs)
+		if err != nil {
+			return vars, err
+		}
+	}
+
+	for _, env := range vars {
+		key, value := split(env)
+		switch {
+		case sp.allowExec && strings.HasPrefix(value, execPrefix):
+			secret, err := sp.runCommand(ctx, strings.TrimPrefix(value, execPrefix))
+			if err != nil {
+				return vars, err
+			}
+			env = key + "=" + secret
+		case strings.HasPrefix(value, pluginPrefix):
+			ref := strings.TrimPrefix(value, pluginPrefix)
+			res, ok := results[ref]
+			if !ok {
+				return vars, errors.Errorf("plugin returned no result for %q", ref)
+			}
+			if res.Error != "" {
+				return vars, errors.Errorf("plugin failed to resolve %q: %s", ref, res.Error)
+			}
+			env = key + "=" + res.Value
+		}
+		envs = append(envs, env)
+	}
+	return envs, nil
+}
+
+// runCommand runs command line and returns its output without trailing newlines
+func (sp *SecretsProvider) runCommand(ctx context.Context, commandLine string) (string, error) {
+	args := strings.Fields(commandLine)
+	if len(args) == 0 {
+		return "", errors.New("empty exec command")
+	}
+	ctx, cancel := context.WithTimeout(ctx, sp.timeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...) //nolint:gosec
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "failed to run %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+// callPlugin resolves references with the plugin
+func (sp *SecretsProvider) callPlugin(ctx context.Context, refs []string) (map[string]PluginResult, error) {
+	if sp.plugin == "" {
+		return nil, errors.New("no exec plugin configured for 'plugin:' references")
+	}
+	req, err := json.Marshal(PluginRequest{References: refs})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode plugin request")
+	}
+	ctx, cancel := context.WithTimeout(ctx, sp.timeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, sp.plugin) //nolint:gosec
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "failed to run plugin %s: %s", sp.plugin, strings.TrimSpace(stderr.String()))
+	}
+	var res PluginResponse
+	if err = json.Unmarshal(stdout.Bytes(), &res); err != nil {
+		return nil, errors.Wrap(err, "failed to decode plugin response")
+	}
+	return res.Results, nil
+}
+
+func split(env string) (key, value string) {
+	kv := strings.SplitN(env, "=", 2) //nolint:gomnd
+	if len(kv) == 1 {
+		return kv[0], ""
+	}
+	return kv[0], kv[1]
+}
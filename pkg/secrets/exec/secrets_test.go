@@ -0,0 +1,118 @@
+// nolint
+package exec
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeScript writes executable shell script to a temporary directory
+func writeScript(t *testing.T, script string) string {
+	path := filepath.Join(t.TempDir(), "script.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSecretsProvider_ResolveSecrets(t *testing.T) {
+	failing := writeScript(t, `echo "test error" >&2; exit 1`)
+	tests := []struct {
+		name      string
+		allowExec bool
+		plugin    string
+		vars      []string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "resolve secret with command output",
+			allowExec: true,
+			vars: []string{
+				"test-secret=exec:" + writeScript(t, `echo "$1-value"`) + " test-secret",
+				"non-secret=hello",
+			},
+			want: []string{
+				"test-secret=test-secret-value",
+				"non-secret=hello",
+			},
+		},
+		{
+			name:      "error running command",
+			allowExec: true,
+			vars: []string{
+				"test-secret=exec:" + failing,
+				"non-secret=hello",
+			},
+			want: []string{
+				"test-secret=exec:" + failing,
+				"non-secret=hello",
+			},
+			wantErr: true,
+		},
+		{
+			name: "exec references not allowed",
+			vars: []string{
+				"test-secret=exec:" + failing,
+				"non-secret=hello",
+			},
+			want: []string{
+				"test-secret=exec:" + failing,
+				"non-secret=hello",
+			},
+		},
+		{
+			name: "resolve secrets with plugin",
+			plugin: writeScript(t, `cat > /dev/null
+echo '{"results": {"db/password": {"value": "test-secret-value-1"}, "db/user": {"value": "test-secret-value-2"}}}'`),
+			vars: []string{
+				"test-secret-1=plugin:db/password",
+				"non-secret=hello",
+				"test-secret-2=plugin:db/user",
+			},
+			want: []string{
+				"test-secret-1=test-secret-value-1",
+				"non-secret=hello",
+				"test-secret-2=test-secret-value-2",
+			},
+		},
+		{
+			name: "plugin fails to resolve reference",
+			plugin: writeScript(t, `cat > /dev/null
+echo '{"results": {"db/password": {"error": "not found"}}}'`),
+			vars: []string{
+				"test-secret=plugin:db/password",
+			},
+			want: []string{
+				"test-secret=plugin:db/password",
+			},
+			wantErr: true,
+		},
+		{
+			name: "no plugin configured",
+			vars: []string{
+				"test-secret=plugin:db/password",
+			},
+			want: []string{
+				"test-secret=plugin:db/password",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := NewExecSecretsProvider(tt.allowExec, tt.plugin, time.Minute)
+			got, err := sp.ResolveSecrets(context.TODO(), tt.vars)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SecretsProvider.ResolveSecrets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ExtractField returns the value at the dot-separated path (e.g. `db.hosts.0.name`) in a decoded JSON or YAML
+// document; strings are returned as is, other scalars are formatted and objects and arrays are JSON encoded
+func ExtractField(doc interface{}, path string) (string, error) {
+	value := doc
+	for _, field := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if value, ok = v[field]; !ok {
+				return "", errors.Errorf("field %q not found", path)
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(field)
+			if err != nil || i < 0 || i >= len(v) {
+				return "", errors.Errorf("field %q not found", path)
+			}
+			value = v[i]
+		default:
+			return "", errors.Errorf("field %q not found", path)
+		}
+	}
+	formatted, err := FormatValue(value)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to encode field %q", path)
+	}
+	return formatted, nil
+}
+
+// FormatValue formats a decoded JSON or YAML value as environment variable value; strings are returned as is,
+// other scalars are formatted and objects and arrays are JSON encoded
+func FormatValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case nil:
+		return "", nil
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to encode value")
+		}
+		return string(b), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
@@ -0,0 +1,132 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"secrets-init/pkg/secrets" //nolint:gci
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3" //nolint:gci
+)
+
+const filePrefix = "file:"
+
+// SecretsProvider local file secrets provider, e.g. for Kubernetes-mounted and Docker secrets
+type SecretsProvider struct{}
+
+// NewFileSecretsProvider init file secrets provider
+func NewFileSecretsProvider() secrets.Provider {
+	return &SecretsProvider{}
+}
+
+// ResolveSecrets replaces all passed variables values prefixed with 'file:' by the referenced file content
+// The reference may have options in query string format
+//
+//	`file:/var/run/secrets/db/password`
+//	`file:/run/secrets/db_password?trim=true`
+//	`file:/var/run/secrets/db/password?decode=base64`
+//	`file:/var/run/secrets/db/config.json#db.password`
+//	`file:/var/run/secrets/db/config?format=yaml#db.password`
+//
+// The `#` suffix selects the value at the dot-separated path in the JSON or YAML file content.
+//
+// Options:
+//   - trim: remove trailing newlines
+//   - decode: decode the file content ('base64')
+//   - key: select the value at the dot-separated path, same as the `#` suffix
+//   - format: file content format for key selection ('json' or 'yaml'); detected from the file extension by default
+func (sp *SecretsProvider) ResolveSecrets(_ context.Context, vars []string) ([]string, error) {
+	envs := make([]string, 0, len(vars))
+	for _, env := range vars {
+		kv := strings.SplitN(env, "=", 2) //nolint:gomnd
+		if len(kv) == 2 && strings.HasPrefix(kv[1], filePrefix) {
+			value, err := readSecret(strings.TrimPrefix(kv[1], filePrefix))
+			if err != nil {
+				return vars, err
+			}
+			env = kv[0] + "=" + value
+		}
+		envs = append(envs, env)
+	}
+	return envs, nil
+}
+
+// readSecret reads secret value from file reference
+func readSecret(ref string) (string, error) {
+	ref, field, hasField := strings.Cut(ref, "#")
+	path, rawQuery, _ := strings.Cut(ref, "?")
+	opts, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid file reference options %q", rawQuery)
+	}
+	key := opts.Get("key")
+	if hasField {
+		if field == "" || key != "" {
+			return "", errors.Errorf("invalid file reference key %q: set either a non-empty '#' suffix or 'key' option", field)
+		}
+		key = field
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read secret file")
+	}
+
+	switch opts.Get("decode") {
+	case "":
+	case "base64":
+		data, err = base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to base64 decode secret file %s", path)
+		}
+	default:
+		return "", errors.Errorf("unsupported decode option %q", opts.Get("decode"))
+	}
+
+	if key != "" {
+		var doc interface{}
+		switch format(path, opts.Get("format")) {
+		case "json":
+			decoder := json.NewDecoder(bytes.NewReader(data))
+			decoder.UseNumber()
+			err = decoder.Decode(&doc)
+		case "yaml":
+			err = yaml.Unmarshal(data, &doc)
+		default:
+			return "", errors.Errorf("unsupported format %q", opts.Get("format"))
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to decode secret file %s", path)
+		}
+		value, err := secrets.ExtractField(doc, key)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to select key from secret file %s", path)
+		}
+		data = []byte(value)
+	}
+
+	value := string(data)
+	if opts.Get("trim") == "true" {
+		value = strings.TrimRight(value, "\r\n")
+	}
+	return value, nil
+}
+
+// format returns the file content format; the format option takes precedence over the file extension
+func format(path, option string) string {
+	if option != "" {
+		return option
+	}
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		return "yaml"
+	default:
+		return "json"
+	}
+}
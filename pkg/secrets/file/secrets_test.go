@@ -0,0 +1,140 @@
+// nolint
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretsProvider_ResolveSecrets(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"password":    "test-secret-value",
+		"docker":      "test-secret-value\n",
+		"encoded":     "dGVzdC1zZWNyZXQtdmFsdWU=\n",
+		"config.json": `{"db": {"password": "test-secret-value", "port": 5432, "hosts": ["a", "b"]}}`,
+		"config.yaml": "db:\n  password: test-secret-value\n  port: 5432\n",
+		"config":      "db:\n  password: test-secret-value\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name    string
+		vars    []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "read secret file",
+			vars: []string{
+				"test-secret=file:" + filepath.Join(dir, "password"),
+				"non-secret=hello",
+			},
+			want: []string{
+				"test-secret=test-secret-value",
+				"non-secret=hello",
+			},
+		},
+		{
+			name: "keep trailing newline by default",
+			vars: []string{"test-secret=file:" + filepath.Join(dir, "docker")},
+			want: []string{"test-secret=test-secret-value\n"},
+		},
+		{
+			name: "trim trailing newline",
+			vars: []string{"test-secret=file:" + filepath.Join(dir, "docker") + "?trim=true"},
+			want: []string{"test-secret=test-secret-value"},
+		},
+		{
+			name: "base64 decode secret file",
+			vars: []string{"test-secret=file:" + filepath.Join(dir, "encoded") + "?decode=base64"},
+			want: []string{"test-secret=test-secret-value"},
+		},
+		{
+			name: "select JSON key",
+			vars: []string{
+				"test-secret=file:" + filepath.Join(dir, "config.json") + "?key=db.password",
+				"test-port=file:" + filepath.Join(dir, "config.json") + "?key=db.port",
+				"test-host=file:" + filepath.Join(dir, "config.json") + "?key=db.hosts.1",
+				"test-hosts=file:" + filepath.Join(dir, "config.json") + "?key=db.hosts",
+			},
+			want: []string{
+				"test-secret=test-secret-value",
+				"test-port=5432",
+				"test-host=b",
+				`test-hosts=["a","b"]`,
+			},
+		},
+		{
+			name: "select YAML key",
+			vars: []string{
+				"test-secret=file:" + filepath.Join(dir, "config.yaml") + "?key=db.password",
+				"test-port=file:" + filepath.Join(dir, "config.yaml") + "?key=db.port",
+			},
+			want: []string{
+				"test-secret=test-secret-value",
+				"test-port=5432",
+			},
+		},
+		{
+			name: "select key with explicit format",
+			vars: []string{"test-secret=file:" + filepath.Join(dir, "config") + "?format=yaml&key=db.password"},
+			want: []string{"test-secret=test-secret-value"},
+		},
+		{
+			name: "select key with '#' suffix",
+			vars: []string{
+				"test-secret=file:" + filepath.Join(dir, "config.json") + "#db.password",
+				"test-port=file:" + filepath.Join(dir, "config.yaml") + "#db.port",
+				"test-host=file:" + filepath.Join(dir, "config") + "?format=yaml#db.password",
+			},
+			want: []string{
+				"test-secret=test-secret-value",
+				"test-port=5432",
+				"test-host=test-secret-value",
+			},
+		},
+		{
+			name:    "error empty '#' suffix",
+			vars:    []string{"test-secret=file:" + filepath.Join(dir, "config.json") + "#"},
+			want:    []string{"test-secret=file:" + filepath.Join(dir, "config.json") + "#"},
+			wantErr: true,
+		},
+		{
+			name:    "error both '#' suffix and key option",
+			vars:    []string{"test-secret=file:" + filepath.Join(dir, "config.json") + "?key=db.port#db.password"},
+			want:    []string{"test-secret=file:" + filepath.Join(dir, "config.json") + "?key=db.port#db.password"},
+			wantErr: true,
+		},
+		{
+			name:    "error missing key",
+			vars:    []string{"test-secret=file:" + filepath.Join(dir, "config.json") + "?key=db.user"},
+			want:    []string{"test-secret=file:" + filepath.Join(dir, "config.json") + "?key=db.user"},
+			wantErr: true,
+		},
+		{
+			name:    "error missing file",
+			vars:    []string{"test-secret=file:" + filepath.Join(dir, "missing")},
+			want:    []string{"test-secret=file:" + filepath.Join(dir, "missing")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := NewFileSecretsProvider()
+			got, err := sp.ResolveSecrets(context.TODO(), tt.vars)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SecretsProvider.ResolveSecrets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
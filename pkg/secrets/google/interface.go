@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/googleapis/gax-go/v2"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
 	secretspb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1"
 )
 
@@ -11,3 +12,8 @@ import (
 type SecretsManagerAPI interface {
 	AccessSecretVersion(ctx context.Context, req *secretspb.AccessSecretVersionRequest, opts ...gax.CallOption) (*secretspb.AccessSecretVersionResponse, error) //nolint:lll
 }
+
+// KMSAPI is the interface for the Google Cloud KMS API.
+type KMSAPI interface {
+	Decrypt(ctx context.Context, req *kmspb.DecryptRequest, opts ...gax.CallOption) (*kmspb.DecryptResponse, error)
+}
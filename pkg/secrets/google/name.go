@@ -0,0 +1,134 @@
+package google
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const latestVersion = "latest"
+
+var (
+	// ErrMalformedName secret reference does not match any of the supported forms
+	ErrMalformedName = errors.New("malformed secret name")
+	// ErrInvalidSecretID secret ID contains invalid characters
+	ErrInvalidSecretID = errors.New("invalid secret ID")
+	// ErrInvalidVersion version is neither a number, 'latest' or an alias
+	ErrInvalidVersion = errors.New("invalid secret version")
+	// ErrUnknownProject short secret name used and the Google project is not known
+	ErrUnknownProject = errors.New("unknown project")
+)
+
+var (
+	secretIDRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,255}$`)
+	// version is a number, 'latest' or an alias
+	versionRe  = regexp.MustCompile(`^[A-Za-z0-9_-]{1,63}$`)
+	projectRe  = regexp.MustCompile(`^[a-z0-9][a-z0-9.:-]*$`)
+	locationRe = regexp.MustCompile(`^[a-z0-9-]+$`)
+)
+
+// NameError malformed Google Secret Manager secret reference
+type NameError struct {
+	Name string
+	Err  error
+}
+
+func (e *NameError) Error() string {
+	return fmt.Sprintf("invalid Google Secret Manager reference %q: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the reason: one of the ErrXxx errors
+func (e *NameError) Unwrap() error {
+	return e.Err
+}
+
+// secretName Google Secret Manager secret version reference; location is set for regional secrets
+type secretName struct {
+	project  string
+	location string
+	secret   string
+	version  string
+}
+
+// parseSecretName parses global, regional and short secret references with optional version (number, 'latest'
+// or alias) as `/versions/{VERSION}` or `@{VERSION}` suffix; short names use the project; the version defaults
+// to 'latest'. A name is a full resource name only when it starts with `projects/{PROJECT}/secrets/` or
+// `projects/{PROJECT}/locations/{LOCATION}/secrets/`
+//
+//	`projects/{PROJECT}/secrets/{SECRET}[/versions/{VERSION}|@{VERSION}]`
+//	`projects/{PROJECT}/locations/{LOCATION}/secrets/{SECRET}[/versions/{VERSION}|@{VERSION}]`
+//	`{SECRET}[/versions/{VERSION}|@{VERSION}]`
+func parseSecretName(name, project string) (secretName, error) {
+	fail := func(err error) (secretName, error) {
+		return secretName{}, &NameError{Name: name, Err: err}
+	}
+
+	ref, tokens, full, err := splitResourceName(name)
+	if err != nil {
+		return fail(err)
+	}
+	if ref.secret, ref.version, err = parseSecretVersion(tokens); err != nil {
+		return fail(err)
+	}
+	if !full {
+		if project == "" {
+			return fail(ErrUnknownProject)
+		}
+		ref.project = project
+	}
+	return ref, nil
+}
+
+// splitResourceName splits the project and location of a full resource name from the remaining
+// `{SECRET}[/versions/{VERSION}|@{VERSION}]` tokens; full is not set for short names
+func splitResourceName(name string) (ref secretName, tokens []string, full bool, err error) {
+	tokens = strings.Split(name, "/")
+	switch {
+	case len(tokens) >= 4 && tokens[0] == "projects" && tokens[2] == "secrets": //nolint:gomnd
+		ref.project, tokens = tokens[1], tokens[3:]
+	case len(tokens) >= 6 && tokens[0] == "projects" && tokens[2] == "locations" && tokens[4] == "secrets": //nolint:gomnd
+		ref.project, ref.location, tokens = tokens[1], tokens[3], tokens[5:]
+		if !locationRe.MatchString(ref.location) {
+			return ref, nil, true, ErrMalformedName
+		}
+	default:
+		// short name; 'projects' is a valid secret ID too
+		return ref, tokens, false, nil
+	}
+	if !projectRe.MatchString(ref.project) {
+		return ref, nil, true, ErrMalformedName
+	}
+	return ref, tokens, true, nil
+}
+
+// parseSecretVersion parses `{SECRET}[/versions/{VERSION}|@{VERSION}]` tokens; the version defaults to 'latest'
+func parseSecretVersion(tokens []string) (secret, version string, err error) {
+	secret, version, hasVersion := strings.Cut(tokens[0], "@")
+	switch {
+	case len(tokens) == 1:
+	case len(tokens) == 3 && tokens[1] == "versions" && !hasVersion: //nolint:gomnd
+		version, hasVersion = tokens[2], true
+	default:
+		return "", "", ErrMalformedName
+	}
+	if !secretIDRe.MatchString(secret) {
+		return "", "", ErrInvalidSecretID
+	}
+	if !hasVersion {
+		return secret, latestVersion, nil
+	}
+	if !versionRe.MatchString(version) {
+		return "", "", ErrInvalidVersion
+	}
+	return secret, version, nil
+}
+
+// String returns the full secret version resource name
+func (ref secretName) String() string {
+	if ref.location != "" {
+		return fmt.Sprintf("projects/%s/locations/%s/secrets/%s/versions/%s", ref.project, ref.location, ref.secret, ref.version)
+	}
+	return fmt.Sprintf("projects/%s/secrets/%s/versions/%s", ref.project, ref.secret, ref.version)
+}
@@ -0,0 +1,72 @@
+// nolint
+package google
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSecretName(t *testing.T) {
+	tests := []struct {
+		name     string
+		project  string
+		want     secretName
+		wantName string
+		wantErr  error
+	}{
+		{name: "projects/p1/secrets/s1", want: secretName{project: "p1", secret: "s1", version: "latest"}, wantName: "projects/p1/secrets/s1/versions/latest"},
+		{name: "projects/p1/secrets/s1/versions/3", want: secretName{project: "p1", secret: "s1", version: "3"}, wantName: "projects/p1/secrets/s1/versions/3"},
+		{name: "projects/p1/secrets/s1/versions/latest", want: secretName{project: "p1", secret: "s1", version: "latest"}, wantName: "projects/p1/secrets/s1/versions/latest"},
+		{name: "projects/p1/secrets/s1/versions/prod", want: secretName{project: "p1", secret: "s1", version: "prod"}, wantName: "projects/p1/secrets/s1/versions/prod"},
+		{name: "projects/123456/secrets/s1", project: "p2", want: secretName{project: "123456", secret: "s1", version: "latest"}, wantName: "projects/123456/secrets/s1/versions/latest"},
+		{name: "projects/p1/locations/europe-west1/secrets/s1", want: secretName{project: "p1", location: "europe-west1", secret: "s1", version: "latest"}, wantName: "projects/p1/locations/europe-west1/secrets/s1/versions/latest"},
+		{name: "projects/p1/locations/europe-west1/secrets/s1/versions/2", want: secretName{project: "p1", location: "europe-west1", secret: "s1", version: "2"}, wantName: "projects/p1/locations/europe-west1/secrets/s1/versions/2"},
+		{name: "s1", project: "p1", want: secretName{project: "p1", secret: "s1", version: "latest"}, wantName: "projects/p1/secrets/s1/versions/latest"},
+		{name: "s1/versions/4", project: "p1", want: secretName{project: "p1", secret: "s1", version: "4"}, wantName: "projects/p1/secrets/s1/versions/4"},
+		{name: "s1/versions/prod", project: "p1", want: secretName{project: "p1", secret: "s1", version: "prod"}, wantName: "projects/p1/secrets/s1/versions/prod"},
+		{name: "s1@prod", project: "p1", want: secretName{project: "p1", secret: "s1", version: "prod"}, wantName: "projects/p1/secrets/s1/versions/prod"},
+		{name: "s1@7", project: "p1", want: secretName{project: "p1", secret: "s1", version: "7"}, wantName: "projects/p1/secrets/s1/versions/7"},
+		{name: "projects/p1/secrets/s1@canary", want: secretName{project: "p1", secret: "s1", version: "canary"}, wantName: "projects/p1/secrets/s1/versions/canary"},
+		{name: "projects/p1/locations/us-east1/secrets/s1@latest", want: secretName{project: "p1", location: "us-east1", secret: "s1", version: "latest"}, wantName: "projects/p1/locations/us-east1/secrets/s1/versions/latest"},
+		{name: "projects", project: "p1", want: secretName{project: "p1", secret: "projects", version: "latest"}, wantName: "projects/p1/secrets/projects/versions/latest"},
+		{name: "projects@prod", project: "p1", want: secretName{project: "p1", secret: "projects", version: "prod"}, wantName: "projects/p1/secrets/projects/versions/prod"},
+		{name: "projects/versions/2", project: "p1", want: secretName{project: "p1", secret: "projects", version: "2"}, wantName: "projects/p1/secrets/projects/versions/2"},
+		{name: "projects", wantErr: ErrUnknownProject},
+		{name: "s1@", project: "p1", wantErr: ErrInvalidVersion},
+		{name: "s1@prod@2", project: "p1", wantErr: ErrInvalidVersion},
+		{name: "s1@prod/versions/2", project: "p1", wantErr: ErrMalformedName},
+		{name: "@prod", project: "p1", wantErr: ErrInvalidSecretID},
+		{name: "my-projects/p1/secrets/s1", project: "p1", wantErr: ErrMalformedName},
+		{name: "s1", wantErr: ErrUnknownProject},
+		{name: "", project: "p1", wantErr: ErrInvalidSecretID},
+		{name: "s.1", project: "p1", wantErr: ErrInvalidSecretID},
+		{name: "s1/version/1", project: "p1", wantErr: ErrMalformedName},
+		{name: "s1/versions/", project: "p1", wantErr: ErrInvalidVersion},
+		{name: "s1/versions/1/extra", project: "p1", wantErr: ErrMalformedName},
+		{name: "projects/p1/secrets", wantErr: ErrMalformedName},
+		{name: "projects//secrets/s1", wantErr: ErrMalformedName},
+		{name: "projects/p1/secret/s1", wantErr: ErrMalformedName},
+		{name: "projects/p1/secrets/s1/version/1", wantErr: ErrMalformedName},
+		{name: "projects/p1/locations/europe-west1/secrets", wantErr: ErrMalformedName},
+		{name: "projects/p1/locations//secrets/s1", wantErr: ErrMalformedName},
+		{name: "projects/p1/locations/europe-west1/keys/s1", wantErr: ErrMalformedName},
+		{name: "projects/p1/secrets/s1/versions/v.1", wantErr: ErrInvalidVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSecretName(tt.name, tt.project)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				var nameErr *NameError
+				assert.True(t, errors.As(err, &nameErr))
+				assert.Equal(t, tt.name, nameErr.Name)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantName, got.String())
+		})
+	}
+}
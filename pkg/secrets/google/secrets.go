@@ -1,41 +1,88 @@
 package google
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
-	"regexp"
+	"hash/crc32"
+	"sort"
 	"strings"
 	"sync"
 
 	"secrets-init/pkg/secrets" //nolint:gci
+	"secrets-init/pkg/tracing"
 
 	"cloud.google.com/go/compute/metadata"
+	kms "cloud.google.com/go/kms/apiv1"
 	secretmanager "cloud.google.com/go/secretmanager/apiv1"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
-	secretspb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1" //nolint:gci
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+	secretspb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1"
+	"google.golang.org/grpc" //nolint:gci
 )
 
-var fullSecretRe = regexp.MustCompile(`projects/[^/]+/secrets/[^/+](/version/[^/+])?`)
+const (
+	kmsPrefix          = "gcpkms:"
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+	// checksumAttempts number of attempts to get secret payload with a valid checksum
+	checksumAttempts = 3
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
 
 type result struct {
-	Env string
-	Err error
+	Envs []string
+	Err  error
+}
+
+// Options Google provider options
+type Options struct {
+	// ProjectID project of secrets without a project prefix; detected with the metadata server if empty
+	ProjectID string
+	// ExpandJSON expand top-level keys of JSON object secrets into environment variables
+	ExpandJSON bool
+	// ImpersonateServiceAccount service account email to impersonate
+	ImpersonateServiceAccount string
+	// Delegates service accounts delegation chain to the impersonated service account
+	Delegates []string
+	// CredentialsFile service account or external account credentials file instead of Application Default Credentials
+	CredentialsFile string
+	// QuotaProject project for quota and billing
+	QuotaProject string
+	// LogVersions log the version resolved for every secret, e.g. for version aliases or 'latest'
+	LogVersions bool
 }
 
 // SecretsProvider Google Cloud secrets provider
 type SecretsProvider struct {
-	sm        SecretsManagerAPI
-	projectID string
+	sm          SecretsManagerAPI
+	projectID   string
+	expand      bool
+	logVersions bool
+	opts        []option.ClientOption
+	// Google Cloud KMS client, created on first use
+	kmsMu sync.Mutex
+	kms   KMSAPI
+	// regional Secret Manager clients by location
+	regionalMu sync.Mutex
+	regional   map[string]SecretsManagerAPI
+	// newRegionalClient creates Secret Manager client of a regional endpoint; newSecretManagerClient if nil
+	newRegionalClient func(ctx context.Context, endpoint string, opts ...option.ClientOption) (SecretsManagerAPI, error)
 }
 
 // NewGoogleSecretsProvider init Google Secrets Provider
-func NewGoogleSecretsProvider(ctx context.Context, projectID string) (secrets.Provider, error) {
-	sp := SecretsProvider{}
+func NewGoogleSecretsProvider(ctx context.Context, o Options) (secrets.Provider, error) {
+	sp := SecretsProvider{expand: o.ExpandJSON, logVersions: o.LogVersions}
 	var err error
 
-	if projectID != "" {
-		sp.projectID = projectID
+	if o.ProjectID != "" {
+		sp.projectID = o.ProjectID
 	} else {
 		sp.projectID, err = metadata.ProjectID()
 		if err != nil {
@@ -43,97 +90,293 @@ func NewGoogleSecretsProvider(ctx context.Context, projectID string) (secrets.Pr
 		}
 	}
 
-	sp.sm, err = secretmanager.NewClient(ctx)
+	opts, err := clientOptions(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	sp.opts = opts
+	sp.sm, err = secretmanager.NewClient(ctx, opts...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to initialize Google Cloud SDK")
 	}
 	return &sp, nil
 }
 
+// clientOptions returns Google Cloud clients options: credentials file, impersonated credentials and quota project
+func clientOptions(ctx context.Context, o Options) ([]option.ClientOption, error) {
+	var creds []option.ClientOption
+	if o.CredentialsFile != "" {
+		creds = append(creds, option.WithCredentialsFile(o.CredentialsFile))
+	}
+	if o.ImpersonateServiceAccount != "" {
+		ts, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+			TargetPrincipal: o.ImpersonateServiceAccount,
+			Scopes:          []string{cloudPlatformScope},
+			Delegates:       o.Delegates,
+		}, creds...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to impersonate service account %s", o.ImpersonateServiceAccount)
+		}
+		creds = []option.ClientOption{option.WithTokenSource(ts)}
+	} else if len(o.Delegates) > 0 {
+		return nil, errors.New("service account delegates require a service account to impersonate")
+	}
+
+	opts := append([]option.ClientOption{
+		option.WithGRPCDialOption(grpc.WithStatsHandler(otelgrpc.NewClientHandler())),
+	}, creds...)
+	if o.QuotaProject != "" {
+		opts = append(opts, option.WithQuotaProject(o.QuotaProject))
+	}
+	return opts, nil
+}
+
+// KMS returns Google Cloud KMS client sharing the provider credentials; the client is created on first use,
+// so the provider works without KMS access
+func (sp *SecretsProvider) KMS(ctx context.Context) (KMSAPI, error) {
+	sp.kmsMu.Lock()
+	defer sp.kmsMu.Unlock()
+	if sp.kms != nil {
+		return sp.kms, nil
+	}
+	client, err := kms.NewKeyManagementClient(ctx, sp.opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialize Google Cloud KMS client")
+	}
+	sp.kms = client
+	return client, nil
+}
+
 // ResolveSecrets replaces all passed variables values prefixed with 'gcp:secretmanager'
 // by corresponding secrets from Google Secret Manager
-// The secret name should be in the format (optionally with version)
+// The secret name should be in the format (optionally with version: number, 'latest' or alias)
 //
 //	`gcp:secretmanager:projects/{PROJECT_ID}/secrets/{SECRET_NAME}`
-//	`gcp:secretmanager:projects/{PROJECT_ID}/secrets/{SECRET_NAME}/versions/{VERSION|latest}`
+//	`gcp:secretmanager:projects/{PROJECT_ID}/secrets/{SECRET_NAME}/versions/{VERSION|latest|ALIAS}`
+//	`gcp:secretmanager:projects/{PROJECT_ID}/locations/{LOCATION}/secrets/{SECRET_NAME}`
+//	`gcp:secretmanager:projects/{PROJECT_ID}/locations/{LOCATION}/secrets/{SECRET_NAME}/versions/{VERSION|latest|ALIAS}`
 //	`gcp:secretmanager:{SECRET_NAME}
-//	`gcp:secretmanager:{SECRET_NAME}/versions/{VERSION|latest}`
-func (sp SecretsProvider) ResolveSecrets(ctx context.Context, vars []string) ([]string, error) {
-	envs := make([]string, 0, len(vars))
+//	`gcp:secretmanager:{SECRET_NAME}/versions/{VERSION|latest|ALIAS}`
+//	`gcp:secretmanager:{SECRET_NAME}@{VERSION|latest|ALIAS}`
+//
+// JSON secrets field is selected with the `#{FIELD}` suffix (dot-separated path, e.g. `#db.password`), and
+// top-level keys of JSON object secrets are expanded into environment variables with the ExpandJSON option
+//
+// Values prefixed with 'gcpkms:' are base64 encoded Google Cloud KMS ciphertexts replaced by the decrypted plaintext
+//
+//	`gcpkms:projects/{PROJECT_ID}/locations/{LOCATION}/keyRings/{KEY_RING}/cryptoKeys/{KEY}:{CIPHERTEXT}`
+func (sp *SecretsProvider) ResolveSecrets(ctx context.Context, vars []string) ([]string, error) {
+	resolved, err := sp.ResolveVariables(ctx, vars)
+	if err != nil {
+		return vars, err
+	}
+	return secrets.Flatten(resolved), nil
+}
 
-	// Create a channel to collect the results
-	results := make(chan result, len(vars))
+// ResolveVariables resolves secrets as ResolveSecrets, returning the environment variables resolved from every variable
+func (sp *SecretsProvider) ResolveVariables(ctx context.Context, vars []string) ([][]string, error) {
+	ctx, span := tracing.Start(ctx, "google.ResolveSecrets", tracing.ProviderKey.String("google"), tracing.ProjectKey.String(sp.projectID))
+	resolved, err := sp.resolveVariables(ctx, vars)
+	tracing.End(span, err)
+	return resolved, err
+}
+
+func (sp *SecretsProvider) resolveVariables(ctx context.Context, vars []string) ([][]string, error) {
+	// release the fetches context when resolution returns
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	// Start a goroutine for each secret
+	// Start a goroutine for each secret, every failure is collected
+	results := make([]result, len(vars))
 	var wg sync.WaitGroup
-	for _, env := range vars {
+	for i, env := range vars {
 		wg.Add(1)
-		go func(env string) {
+		go func(i int, env string) {
 			defer wg.Done()
-			select {
-			case <-ctx.Done():
-				results <- result{Err: ctx.Err()}
-				return
-			default:
-				val, err := sp.processEnvironmentVariable(ctx, env)
-				if err != nil {
-					results <- result{Err: err}
-					return
-				}
-				results <- result{Env: val}
-			}
-		}(env)
-	}
-
-	// Start another goroutine to close the results channel when all fetch goroutines are done
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Collect the results
-	for res := range results {
+			envs, err := sp.processEnvironmentVariable(ctx, env)
+			results[i] = result{Envs: envs, Err: err}
+		}(i, env)
+	}
+	wg.Wait()
+
+	resolved := make([][]string, len(vars))
+	var errs []*secrets.ResolveError
+	for i, res := range results {
 		if res.Err != nil {
-			return vars, res.Err
+			key, value, _ := strings.Cut(vars[i], "=")
+			errs = append(errs, &secrets.ResolveError{Key: key, Ref: value, Err: res.Err})
+			continue
 		}
-		envs = append(envs, res.Env)
+		resolved[i] = res.Envs
 	}
-
-	return envs, nil
+	if len(errs) > 0 {
+		return nil, secrets.NewResolveErrors(errs)
+	}
+	return resolved, nil
 }
 
-// processEnvironmentVariable processes the environment variable and replaces the value with the secret value
-func (sp SecretsProvider) processEnvironmentVariable(ctx context.Context, env string) (string, error) {
-	kv := strings.Split(env, "=")
+// processEnvironmentVariable processes the environment variable and replaces the value with the secret value;
+// expanded JSON secrets replace the variable with a variable per key
+func (sp *SecretsProvider) processEnvironmentVariable(ctx context.Context, env string) ([]string, error) {
+	kv := strings.SplitN(env, "=", 2) //nolint:gomnd
 	key, value := kv[0], kv[1]
+	if strings.HasPrefix(value, kmsPrefix) {
+		plaintext, err := sp.decrypt(ctx, strings.TrimPrefix(value, kmsPrefix))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decrypt Google Cloud KMS ciphertext")
+		}
+		return []string{key + "=" + string(plaintext)}, nil
+	}
 	if !strings.HasPrefix(value, "gcp:secretmanager:") {
-		return env, nil
+		return []string{env}, nil
+	}
+
+	name, field, hasField := strings.Cut(strings.TrimPrefix(value, "gcp:secretmanager:"), "#")
+	ref, err := parseSecretName(name, sp.projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// get secret value
+	client, err := sp.client(ctx, ref.location)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := sp.accessSecretPayload(ctx, client, ref.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret from Google Secret Manager: %w", err)
+	}
+	if sp.logVersions {
+		log.WithFields(log.Fields{"env": key, "secret": ref.String(), "version": secret.GetName()}).Info("resolved Google secret version")
+	}
+	data := secret.GetPayload().GetData()
+	if hasField {
+		var doc interface{}
+		if err = decodeJSON(data, &doc); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode JSON secret %s", ref)
+		}
+		value, err := secrets.ExtractField(doc, field)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to select field from secret %s", ref)
+		}
+		return []string{key + "=" + value}, nil
+	}
+	if sp.expand {
+		var doc map[string]interface{}
+		if decodeJSON(data, &doc) == nil && doc != nil {
+			return expandJSON(doc)
+		}
 	}
+	return []string{key + "=" + string(data)}, nil
+}
 
-	// construct valid secret name
-	name := strings.TrimPrefix(value, "gcp:secretmanager:")
+// decodeJSON decodes JSON keeping numbers as is
+func decodeJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v) //nolint:wrapcheck
+}
 
-	isLong := fullSecretRe.MatchString(name)
+// expandJSON returns environment variable for every top-level key of JSON object secret
+func expandJSON(doc map[string]interface{}) ([]string, error) {
+	envs := make([]string, 0, len(doc))
+	for key, value := range doc {
+		formatted, err := secrets.FormatValue(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encode secret key %q", key)
+		}
+		envs = append(envs, key+"="+formatted)
+	}
+	sort.Strings(envs)
+	return envs, nil
+}
 
-	if !isLong {
-		if sp.projectID == "" {
-			return "", errors.Errorf("failed to get secret \"%s\" from Google Secret Manager (unknown project)", name)
+// accessSecretPayload gets secret version payload, verifying its CRC32C checksum when present;
+// a corrupted payload is fetched again up to checksumAttempts times
+func (sp *SecretsProvider) accessSecretPayload(ctx context.Context, client SecretsManagerAPI, name string) (*secretspb.AccessSecretVersionResponse, error) {
+	for attempt := 1; ; attempt++ {
+		secret, err := sp.accessSecretVersion(ctx, client, name)
+		if err != nil {
+			return nil, err
+		}
+		payload := secret.GetPayload()
+		if payload.DataCrc32C == nil || int64(crc32.Checksum(payload.GetData(), crc32cTable)) == payload.GetDataCrc32C() {
+			return secret, nil
 		}
-		name = fmt.Sprintf("projects/%s/secrets/%s", sp.projectID, name)
+		if attempt == checksumAttempts {
+			return nil, errors.Errorf("secret %s payload is corrupted: CRC32C checksum mismatch after %d attempts", name, attempt)
+		}
+		log.WithField("attempt", attempt).Warn("Google Secret Manager payload CRC32C checksum mismatch, retrying")
 	}
+}
 
-	// if no version specified add latest
-	if !strings.Contains(name, "/versions/") {
-		name += "/versions/latest"
+// accessSecretVersion gets secret version from Google Secret Manager
+func (sp *SecretsProvider) accessSecretVersion(ctx context.Context, client SecretsManagerAPI, name string) (*secretspb.AccessSecretVersionResponse, error) {
+	ctx, span := tracing.Start(ctx, "secretmanager.AccessSecretVersion", tracing.SecretName(name)...)
+	secret, err := client.AccessSecretVersion(ctx, &secretspb.AccessSecretVersionRequest{Name: name})
+	tracing.End(span, err)
+	return secret, err //nolint:wrapcheck
+}
+
+// client returns Secret Manager client for location: the global client if empty and a client of the
+// `secretmanager.{LOCATION}.rep.googleapis.com` regional endpoint otherwise
+func (sp *SecretsProvider) client(ctx context.Context, location string) (SecretsManagerAPI, error) {
+	if location == "" {
+		return sp.sm, nil
+	}
+	sp.regionalMu.Lock()
+	defer sp.regionalMu.Unlock()
+	if client, ok := sp.regional[location]; ok {
+		return client, nil
 	}
+	newClient := sp.newRegionalClient
+	if newClient == nil {
+		newClient = newSecretManagerClient
+	}
+	client, err := newClient(ctx, fmt.Sprintf("secretmanager.%s.rep.googleapis.com:443", location), sp.opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to initialize Google Secret Manager client for location %s", location)
+	}
+	if sp.regional == nil {
+		sp.regional = make(map[string]SecretsManagerAPI)
+	}
+	sp.regional[location] = client
+	return client, nil
+}
 
-	// get secret value
-	req := &secretspb.AccessSecretVersionRequest{
-		Name: name,
+// newSecretManagerClient creates Secret Manager client of the endpoint
+func newSecretManagerClient(ctx context.Context, endpoint string, opts ...option.ClientOption) (SecretsManagerAPI, error) {
+	opts = append(append([]option.ClientOption{}, opts...), option.WithEndpoint(endpoint))
+	return secretmanager.NewClient(ctx, opts...) //nolint:wrapcheck
+}
+
+// decrypt decrypts base64 encoded ciphertext with the Google Cloud KMS key: `{KEY_NAME}:{CIPHERTEXT}`
+func (sp *SecretsProvider) decrypt(ctx context.Context, ref string) (_ []byte, err error) {
+	name, encoded, ok := cutLast(ref, ":")
+	if !ok || !strings.Contains(name, "/cryptoKeys/") {
+		return nil, errors.New("invalid Google Cloud KMS reference, expected {KEY_NAME}:{CIPHERTEXT}")
+	}
+	ctx, span := tracing.Start(ctx, "kms.Decrypt", tracing.SecretName(name)...)
+	defer func() { tracing.End(span, err) }()
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid base64 ciphertext")
 	}
-	secret, err := sp.sm.AccessSecretVersion(ctx, req)
+	client, err := sp.KMS(ctx)
 	if err != nil {
-		return "", fmt.Errorf("failed to get secret from Google Secret Manager: %w", err)
+		return nil, err
+	}
+	out, err := client.Decrypt(ctx, &kmspb.DecryptRequest{Name: name, Ciphertext: ciphertext})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	return out.Plaintext, nil
+}
+
+// cutLast slices s around the last instance of sep
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
 	}
-	return key + "=" + string(secret.Payload.GetData()), nil
+	return s, "", false
 }
@@ -3,13 +3,32 @@ package google
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"secrets-init/mocks"
 	"secrets-init/pkg/secrets"
 
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
 	secretspb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1"
 )
 
@@ -48,7 +67,37 @@ func TestSecretsProvider_ResolveSecrets(t *testing.T) {
 				res := secretspb.AccessSecretVersionResponse{Payload: &secretspb.SecretPayload{
 					Data: []byte("test-secret-value"),
 				}}
-				mockSM.On("AccessSecretVersion", ctx, &req).Return(&res, nil)
+				mockSM.On("AccessSecretVersion", mock.Anything, &req).Return(&res, nil)
+				return &sp
+			},
+		},
+		{
+			name: "get regional secret from the regional Secrets Manager endpoint",
+			args: args{
+				ctx: context.TODO(),
+				vars: []string{
+					"test-secret=gcp:secretmanager:projects/test-project-id/locations/europe-west1/secrets/test-secret",
+				},
+			},
+			want: []string{
+				"test-secret=test-secret-value",
+			},
+			mockServiceProvider: func(ctx context.Context, mockSM *mocks.GoogleSecretsManagerAPI) secrets.Provider {
+				regionalSM := &mocks.GoogleSecretsManagerAPI{}
+				sp := SecretsProvider{sm: mockSM}
+				sp.newRegionalClient = func(_ context.Context, endpoint string, _ ...option.ClientOption) (SecretsManagerAPI, error) {
+					if endpoint != "secretmanager.europe-west1.rep.googleapis.com:443" {
+						return nil, fmt.Errorf("unexpected endpoint %s", endpoint)
+					}
+					return regionalSM, nil
+				}
+				req := secretspb.AccessSecretVersionRequest{
+					Name: "projects/test-project-id/locations/europe-west1/secrets/test-secret/versions/latest",
+				}
+				res := secretspb.AccessSecretVersionResponse{Payload: &secretspb.SecretPayload{
+					Data: []byte("test-secret-value"),
+				}}
+				regionalSM.On("AccessSecretVersion", mock.Anything, &req).Return(&res, nil)
 				return &sp
 			},
 		},
@@ -71,7 +120,7 @@ func TestSecretsProvider_ResolveSecrets(t *testing.T) {
 				res := secretspb.AccessSecretVersionResponse{Payload: &secretspb.SecretPayload{
 					Data: []byte("test-secret-value"),
 				}}
-				mockSM.On("AccessSecretVersion", ctx, &req).Return(&res, nil)
+				mockSM.On("AccessSecretVersion", mock.Anything, &req).Return(&res, nil)
 				return &sp
 			},
 		},
@@ -94,7 +143,7 @@ func TestSecretsProvider_ResolveSecrets(t *testing.T) {
 				res := secretspb.AccessSecretVersionResponse{Payload: &secretspb.SecretPayload{
 					Data: []byte("test-secret-value"),
 				}}
-				mockSM.On("AccessSecretVersion", ctx, &req).Return(&res, nil)
+				mockSM.On("AccessSecretVersion", mock.Anything, &req).Return(&res, nil)
 				return &sp
 			},
 		},
@@ -117,7 +166,7 @@ func TestSecretsProvider_ResolveSecrets(t *testing.T) {
 				res := secretspb.AccessSecretVersionResponse{Payload: &secretspb.SecretPayload{
 					Data: []byte("test-secret-value"),
 				}}
-				mockSM.On("AccessSecretVersion", ctx, &req).Return(&res, nil)
+				mockSM.On("AccessSecretVersion", mock.Anything, &req).Return(&res, nil)
 				return &sp
 			},
 		},
@@ -151,7 +200,7 @@ func TestSecretsProvider_ResolveSecrets(t *testing.T) {
 					res := secretspb.AccessSecretVersionResponse{Payload: &secretspb.SecretPayload{
 						Data: []byte(value),
 					}}
-					mockSM.On("AccessSecretVersion", ctx, &req).Return(&res, nil)
+					mockSM.On("AccessSecretVersion", mock.Anything, &req).Return(&res, nil)
 				}
 				return &sp
 			},
@@ -173,6 +222,113 @@ func TestSecretsProvider_ResolveSecrets(t *testing.T) {
 				return &SecretsProvider{sm: mockSM}
 			},
 		},
+		{
+			name: "select JSON secret field",
+			args: args{
+				ctx:  context.TODO(),
+				vars: []string{"test-secret=gcp:secretmanager:test-secret#user"},
+			},
+			want: []string{
+				"test-secret=admin",
+			},
+			mockServiceProvider: func(ctx context.Context, mockSM *mocks.GoogleSecretsManagerAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, projectID: "test-project-id"}
+				req := secretspb.AccessSecretVersionRequest{
+					Name: "projects/test-project-id/secrets/test-secret/versions/latest",
+				}
+				res := secretspb.AccessSecretVersionResponse{Payload: &secretspb.SecretPayload{
+					Data: []byte(`{"user":"admin","db":{"password":"p4ss","port":5432}}`),
+				}}
+				mockSM.On("AccessSecretVersion", mock.Anything, &req).Return(&res, nil)
+				return &sp
+			},
+		},
+		{
+			name: "select nested JSON secret field",
+			args: args{
+				ctx:  context.TODO(),
+				vars: []string{"test-secret=gcp:secretmanager:test-secret#db.port"},
+			},
+			want: []string{
+				"test-secret=5432",
+			},
+			mockServiceProvider: func(ctx context.Context, mockSM *mocks.GoogleSecretsManagerAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, projectID: "test-project-id"}
+				req := secretspb.AccessSecretVersionRequest{
+					Name: "projects/test-project-id/secrets/test-secret/versions/latest",
+				}
+				res := secretspb.AccessSecretVersionResponse{Payload: &secretspb.SecretPayload{
+					Data: []byte(`{"user":"admin","db":{"password":"p4ss","port":5432}}`),
+				}}
+				mockSM.On("AccessSecretVersion", mock.Anything, &req).Return(&res, nil)
+				return &sp
+			},
+		},
+		{
+			name: "select missing JSON secret field",
+			args: args{
+				ctx:  context.TODO(),
+				vars: []string{"test-secret=gcp:secretmanager:test-secret#db.host"},
+			},
+			want: []string{
+				"test-secret=gcp:secretmanager:test-secret#db.host",
+			},
+			wantErr: true,
+			mockServiceProvider: func(ctx context.Context, mockSM *mocks.GoogleSecretsManagerAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, projectID: "test-project-id"}
+				req := secretspb.AccessSecretVersionRequest{
+					Name: "projects/test-project-id/secrets/test-secret/versions/latest",
+				}
+				res := secretspb.AccessSecretVersionResponse{Payload: &secretspb.SecretPayload{
+					Data: []byte(`{"user":"admin","db":{"password":"p4ss","port":5432}}`),
+				}}
+				mockSM.On("AccessSecretVersion", mock.Anything, &req).Return(&res, nil)
+				return &sp
+			},
+		},
+		{
+			name: "expand JSON secret keys",
+			args: args{
+				ctx:  context.TODO(),
+				vars: []string{"test-secret=gcp:secretmanager:test-secret"},
+			},
+			want: []string{
+				"user=admin",
+				`db={"password":"p4ss","port":5432}`,
+			},
+			mockServiceProvider: func(ctx context.Context, mockSM *mocks.GoogleSecretsManagerAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, projectID: "test-project-id", expand: true}
+				req := secretspb.AccessSecretVersionRequest{
+					Name: "projects/test-project-id/secrets/test-secret/versions/latest",
+				}
+				res := secretspb.AccessSecretVersionResponse{Payload: &secretspb.SecretPayload{
+					Data: []byte(`{"user":"admin","db":{"password":"p4ss","port":5432}}`),
+				}}
+				mockSM.On("AccessSecretVersion", mock.Anything, &req).Return(&res, nil)
+				return &sp
+			},
+		},
+		{
+			name: "JSON secret not expanded by default",
+			args: args{
+				ctx:  context.TODO(),
+				vars: []string{"test-secret=gcp:secretmanager:test-secret"},
+			},
+			want: []string{
+				`test-secret={"user":"admin","db":{"password":"p4ss","port":5432}}`,
+			},
+			mockServiceProvider: func(ctx context.Context, mockSM *mocks.GoogleSecretsManagerAPI) secrets.Provider {
+				sp := SecretsProvider{sm: mockSM, projectID: "test-project-id"}
+				req := secretspb.AccessSecretVersionRequest{
+					Name: "projects/test-project-id/secrets/test-secret/versions/latest",
+				}
+				res := secretspb.AccessSecretVersionResponse{Payload: &secretspb.SecretPayload{
+					Data: []byte(`{"user":"admin","db":{"password":"p4ss","port":5432}}`),
+				}}
+				mockSM.On("AccessSecretVersion", mock.Anything, &req).Return(&res, nil)
+				return &sp
+			},
+		},
 		{
 			name: "error getting secret from Secrets Manager",
 			args: args{
@@ -191,7 +347,7 @@ func TestSecretsProvider_ResolveSecrets(t *testing.T) {
 				req := secretspb.AccessSecretVersionRequest{
 					Name: "projects/test-project-id/secrets/test-secret/versions/latest",
 				}
-				mockSM.On("AccessSecretVersion", ctx, &req).Return(nil, errors.New("test error"))
+				mockSM.On("AccessSecretVersion", mock.Anything, &req).Return(nil, errors.New("test error"))
 				return &sp
 			},
 		},
@@ -212,3 +368,343 @@ func TestSecretsProvider_ResolveSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestSecretsProvider_RegionalClient(t *testing.T) {
+	global := &mocks.GoogleSecretsManagerAPI{}
+	var endpoints []string
+	sp := &SecretsProvider{sm: global}
+	sp.newRegionalClient = func(_ context.Context, endpoint string, _ ...option.ClientOption) (SecretsManagerAPI, error) {
+		endpoints = append(endpoints, endpoint)
+		return &mocks.GoogleSecretsManagerAPI{}, nil
+	}
+
+	client, err := sp.client(context.TODO(), "")
+	require.NoError(t, err)
+	assert.Same(t, global, client)
+	regional, err := sp.client(context.TODO(), "europe-west1")
+	require.NoError(t, err)
+	again, err := sp.client(context.TODO(), "europe-west1")
+	require.NoError(t, err)
+	// regional clients are created once per location
+	assert.Same(t, regional, again)
+	assert.Equal(t, []string{"secretmanager.europe-west1.rep.googleapis.com:443"}, endpoints)
+}
+
+func TestSecretsProvider_ResolveSecrets_Checksum(t *testing.T) {
+	const name = "projects/test-project-id/secrets/test-secret/versions/latest"
+	payload := func(data string, checksum int64) *secretspb.AccessSecretVersionResponse {
+		return &secretspb.AccessSecretVersionResponse{Payload: &secretspb.SecretPayload{Data: []byte(data), DataCrc32C: &checksum}}
+	}
+	valid := int64(crc32.Checksum([]byte("test-secret-value"), crc32.MakeTable(crc32.Castagnoli)))
+	tests := []struct {
+		name    string
+		mockSM  func(*mocks.GoogleSecretsManagerAPI)
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "valid checksum",
+			mockSM: func(mockSM *mocks.GoogleSecretsManagerAPI) {
+				mockSM.On("AccessSecretVersion", mock.Anything, &secretspb.AccessSecretVersionRequest{Name: name}).
+					Return(payload("test-secret-value", valid), nil).Once()
+			},
+			want: []string{"test-secret=test-secret-value"},
+		},
+		{
+			name: "checksum mismatch retried",
+			mockSM: func(mockSM *mocks.GoogleSecretsManagerAPI) {
+				mockSM.On("AccessSecretVersion", mock.Anything, &secretspb.AccessSecretVersionRequest{Name: name}).
+					Return(payload("test-secret-valuf", valid), nil).Once()
+				mockSM.On("AccessSecretVersion", mock.Anything, &secretspb.AccessSecretVersionRequest{Name: name}).
+					Return(payload("test-secret-value", valid), nil).Once()
+			},
+			want: []string{"test-secret=test-secret-value"},
+		},
+		{
+			name: "persistent checksum mismatch",
+			mockSM: func(mockSM *mocks.GoogleSecretsManagerAPI) {
+				mockSM.On("AccessSecretVersion", mock.Anything, &secretspb.AccessSecretVersionRequest{Name: name}).
+					Return(payload("test-secret-valuf", valid), nil).Times(checksumAttempts)
+			},
+			want:    []string{"test-secret=gcp:secretmanager:test-secret"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSM := &mocks.GoogleSecretsManagerAPI{}
+			tt.mockSM(mockSM)
+			sp := &SecretsProvider{sm: mockSM, projectID: "test-project-id"}
+			got, err := sp.ResolveSecrets(context.TODO(), []string{"test-secret=gcp:secretmanager:test-secret"})
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "checksum mismatch")
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+			mockSM.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSecretsProvider_ResolveSecrets_KMS(t *testing.T) {
+	const keyName = "projects/test-project-id/locations/global/keyRings/test-ring/cryptoKeys/test-key"
+	tests := []struct {
+		name    string
+		vars    []string
+		mockKMS func(*mocks.GoogleKMSAPI)
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "decrypt inline Google Cloud KMS ciphertext",
+			vars: []string{
+				"test-secret=gcpkms:" + keyName + ":Y2lwaGVydGV4dA==",
+				"non-secret=hello",
+			},
+			want: []string{
+				"test-secret=test-secret-value",
+				"non-secret=hello",
+			},
+			mockKMS: func(mockKMS *mocks.GoogleKMSAPI) {
+				req := kmspb.DecryptRequest{Name: keyName, Ciphertext: []byte("ciphertext")}
+				mockKMS.On("Decrypt", mock.Anything, &req).Return(&kmspb.DecryptResponse{Plaintext: []byte("test-secret-value")}, nil)
+			},
+		},
+		{
+			name: "error missing key name",
+			vars: []string{
+				"test-secret=gcpkms:Y2lwaGVydGV4dA==",
+			},
+			want: []string{
+				"test-secret=gcpkms:Y2lwaGVydGV4dA==",
+			},
+			mockKMS: func(mockKMS *mocks.GoogleKMSAPI) {},
+			wantErr: true,
+		},
+		{
+			name: "error decrypting Google Cloud KMS ciphertext",
+			vars: []string{
+				"test-secret=gcpkms:" + keyName + ":Y2lwaGVydGV4dA==",
+			},
+			want: []string{
+				"test-secret=gcpkms:" + keyName + ":Y2lwaGVydGV4dA==",
+			},
+			mockKMS: func(mockKMS *mocks.GoogleKMSAPI) {
+				mockKMS.On("Decrypt", mock.Anything, mock.Anything).Return(nil, errors.New("test error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockKMS := mocks.NewGoogleKMSAPI(t)
+			tt.mockKMS(mockKMS)
+			sp := &SecretsProvider{sm: &mocks.GoogleSecretsManagerAPI{}, kms: mockKMS}
+			got, err := sp.ResolveSecrets(context.TODO(), tt.vars)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SecretsProvider.ResolveSecrets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestSecretsProvider_KMSClientFailure(t *testing.T) {
+	const keyName = "projects/test-project-id/locations/global/keyRings/test-ring/cryptoKeys/test-key"
+	mockSM := &mocks.GoogleSecretsManagerAPI{}
+	mockSM.On("AccessSecretVersion", mock.Anything, &secretspb.AccessSecretVersionRequest{Name: "projects/test-project-id/secrets/test-secret/versions/latest"}).
+		Return(&secretspb.AccessSecretVersionResponse{Payload: &secretspb.SecretPayload{Data: []byte("test-secret-value")}}, nil)
+	// the KMS client can not be created without credentials
+	sp := &SecretsProvider{sm: mockSM, opts: []option.ClientOption{option.WithCredentialsFile(filepath.Join(t.TempDir(), "missing.json"))}}
+
+	got, err := sp.ResolveSecrets(context.TODO(), []string{"test-secret=gcp:secretmanager:projects/test-project-id/secrets/test-secret"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test-secret=test-secret-value"}, got)
+
+	_, err = sp.ResolveSecrets(context.TODO(), []string{"test-secret=gcpkms:" + keyName + ":Y2lwaGVydGV4dA=="})
+	assert.ErrorContains(t, err, "failed to initialize Google Cloud KMS client")
+}
+
+func TestSecretsProvider_ResolveSecrets_LogVersions(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	mockSM := &mocks.GoogleSecretsManagerAPI{}
+	mockSM.On("AccessSecretVersion", mock.Anything, &secretspb.AccessSecretVersionRequest{Name: "projects/test-project-id/secrets/test-secret/versions/prod"}).
+		Return(&secretspb.AccessSecretVersionResponse{
+			Name:    "projects/123456789/secrets/test-secret/versions/5",
+			Payload: &secretspb.SecretPayload{Data: []byte("test-secret-value")},
+		}, nil)
+	sp := &SecretsProvider{sm: mockSM, projectID: "test-project-id", logVersions: true}
+
+	got, err := sp.ResolveSecrets(context.TODO(), []string{"test-secret=gcp:secretmanager:test-secret@prod"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test-secret=test-secret-value"}, got)
+	if assert.Len(t, hook.Entries, 1) {
+		assert.Equal(t, "test-secret", hook.LastEntry().Data["env"])
+		assert.Equal(t, "projects/test-project-id/secrets/test-secret/versions/prod", hook.LastEntry().Data["secret"])
+		assert.Equal(t, "projects/123456789/secrets/test-secret/versions/5", hook.LastEntry().Data["version"])
+	}
+	mockSM.AssertExpectations(t)
+}
+
+func TestSecretsProvider_ResolveSecrets_Errors(t *testing.T) {
+	mockSM := &mocks.GoogleSecretsManagerAPI{}
+	mockSM.On("AccessSecretVersion", mock.Anything, &secretspb.AccessSecretVersionRequest{Name: "projects/test-project-id/secrets/missing/versions/latest"}).
+		Return(nil, errors.New("not found"))
+	mockSM.On("AccessSecretVersion", mock.Anything, &secretspb.AccessSecretVersionRequest{Name: "projects/test-project-id/secrets/denied/versions/2"}).
+		Return(nil, errors.New("permission denied"))
+	mockSM.On("AccessSecretVersion", mock.Anything, &secretspb.AccessSecretVersionRequest{Name: "projects/test-project-id/secrets/found/versions/latest"}).
+		Return(&secretspb.AccessSecretVersionResponse{Payload: &secretspb.SecretPayload{Data: []byte("value")}}, nil)
+	sp := &SecretsProvider{sm: mockSM, projectID: "test-project-id"}
+
+	vars := []string{
+		"MISSING=gcp:secretmanager:missing",
+		"FOUND=gcp:secretmanager:found",
+		"DENIED=gcp:secretmanager:denied@2",
+		"INVALID=gcp:secretmanager:invalid/version/2",
+		"non-secret=hello",
+	}
+	got, err := sp.ResolveSecrets(context.TODO(), vars)
+	assert.Equal(t, vars, got)
+	var errs secrets.ResolveErrors
+	if assert.True(t, errors.As(err, &errs)) && assert.Len(t, errs, 3) {
+		assert.Equal(t, "DENIED", errs[0].Key)
+		assert.Equal(t, "gcp:secretmanager:denied@2", errs[0].Ref)
+		assert.ErrorContains(t, errs[0], "permission denied")
+		assert.Equal(t, "INVALID", errs[1].Key)
+		assert.ErrorIs(t, errs[1], ErrMalformedName)
+		assert.Equal(t, "MISSING", errs[2].Key)
+		assert.ErrorContains(t, errs[2], "not found")
+	}
+	assert.ErrorContains(t, err, "failed to resolve 3 secret(s)")
+	mockSM.AssertExpectations(t)
+}
+
+// googleStub fake Google OAuth2, IAM credentials and API endpoints recording requests
+type googleStub struct {
+	mu           sync.Mutex
+	target       string
+	delegates    []string
+	auth         string
+	quotaProject string
+}
+
+func (g *googleStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Host == "oauth2.googleapis.com":
+		_, _ = io.WriteString(w, `{"access_token": "base-token", "token_type": "Bearer", "expires_in": 3600}`)
+	case r.Host == "iamcredentials.googleapis.com":
+		var req struct {
+			Delegates []string `json:"delegates"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		g.target = strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/projects/-/serviceAccounts/"), ":generateAccessToken")
+		g.delegates = req.Delegates
+		_, _ = io.WriteString(w, `{"accessToken": "impersonated-token", "expireTime": "`+time.Now().Add(time.Hour).Format(time.RFC3339)+`"}`)
+	default:
+		g.auth = r.Header.Get("Authorization")
+		g.quotaProject = r.Header.Get("X-Goog-User-Project")
+		_, _ = io.WriteString(w, `{}`)
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	credentials := filepath.Join(t.TempDir(), "credentials.json")
+	err := os.WriteFile(credentials, []byte(`{
+		"type": "authorized_user",
+		"client_id": "test-client-id",
+		"client_secret": "test-client-secret",
+		"refresh_token": "test-refresh-token"
+	}`), 0o600)
+	require.NoError(t, err)
+
+	// route all Google endpoints to the stub
+	stub := &googleStub{}
+	srv := httptest.NewTLSServer(stub)
+	defer srv.Close()
+	defaultTransport := http.DefaultTransport
+	defer func() { http.DefaultTransport = defaultTransport }()
+	http.DefaultTransport = &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+		},
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+
+	tests := []struct {
+		name             string
+		opts             Options
+		wantAuth         string
+		wantQuotaProject string
+		wantTarget       string
+		wantDelegates    []string
+		wantErr          string
+	}{
+		{
+			name:             "credentials file and quota project",
+			opts:             Options{CredentialsFile: credentials, QuotaProject: "billing"},
+			wantAuth:         "Bearer base-token",
+			wantQuotaProject: "billing",
+		},
+		{
+			name: "impersonated service account",
+			opts: Options{
+				ImpersonateServiceAccount: "reader@test-project-id.iam.gserviceaccount.com",
+				Delegates:                 []string{"delegate@test-project-id.iam.gserviceaccount.com"},
+				CredentialsFile:           credentials,
+				QuotaProject:              "billing",
+			},
+			wantAuth:         "Bearer impersonated-token",
+			wantQuotaProject: "billing",
+			wantTarget:       "reader@test-project-id.iam.gserviceaccount.com",
+			wantDelegates:    []string{"projects/-/serviceAccounts/delegate@test-project-id.iam.gserviceaccount.com"},
+		},
+		{
+			name:       "impersonated service account without delegates",
+			opts:       Options{ImpersonateServiceAccount: "reader@test-project-id.iam.gserviceaccount.com", CredentialsFile: credentials},
+			wantAuth:   "Bearer impersonated-token",
+			wantTarget: "reader@test-project-id.iam.gserviceaccount.com",
+		},
+		{
+			name:    "impersonation with missing credentials file",
+			opts:    Options{ImpersonateServiceAccount: "reader@test-project-id.iam.gserviceaccount.com", CredentialsFile: filepath.Join(t.TempDir(), "missing.json")},
+			wantErr: "failed to impersonate service account reader@test-project-id.iam.gserviceaccount.com",
+		},
+		{
+			name:    "delegates without impersonated service account",
+			opts:    Options{Delegates: []string{"delegate@test-project-id.iam.gserviceaccount.com"}},
+			wantErr: "service account delegates require a service account to impersonate",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*stub = googleStub{}
+			got, err := clientOptions(context.TODO(), tt.opts)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			// call an API with the options
+			client, _, err := htransport.NewClient(context.TODO(), got...)
+			require.NoError(t, err)
+			resp, err := client.Get("https://secretmanager.googleapis.com/v1/projects/test-project-id/secrets")
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			stub.mu.Lock()
+			defer stub.mu.Unlock()
+			assert.Equal(t, tt.wantAuth, stub.auth)
+			assert.Equal(t, tt.wantQuotaProject, stub.quotaProject)
+			assert.Equal(t, tt.wantTarget, stub.target)
+			assert.Equal(t, tt.wantDelegates, stub.delegates)
+		})
+	}
+}
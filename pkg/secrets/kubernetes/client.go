@@ -0,0 +1,94 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	maxErrorBodySize  = 1024
+)
+
+// Client minimal Kubernetes API client reading secrets
+type Client struct {
+	host       string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient init Kubernetes API client for host, authenticated with bearer token
+func NewClient(host, token string, httpClient *http.Client) *Client {
+	return &Client{host: strings.TrimSuffix(host, "/"), token: token, httpClient: httpClient}
+}
+
+// NewInClusterClient init Kubernetes API client with the pod service account
+func NewInClusterClient() (*Client, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("not running inside a Kubernetes cluster")
+	}
+	token, err := os.ReadFile(serviceAccountDir + "/token")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read service account token")
+	}
+	ca, err := os.ReadFile(serviceAccountDir + "/ca.crt")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read service account CA certificate")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("invalid service account CA certificate")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	return NewClient("https://"+net.JoinHostPort(host, port), strings.TrimSpace(string(token)),
+		&http.Client{Transport: transport}), nil
+}
+
+// InClusterNamespace returns the pod namespace
+func InClusterNamespace() string {
+	ns, err := os.ReadFile(serviceAccountDir + "/namespace")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(ns))
+}
+
+// GetSecret gets secret data (decoded) from Kubernetes API
+func (c *Client) GetSecret(ctx context.Context, namespace, name string) (map[string][]byte, error) {
+	u := fmt.Sprintf("%s/api/v1/namespaces/%s/secrets/%s", c.host, url.PathEscape(namespace), url.PathEscape(name))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Kubernetes API request")
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to call Kubernetes API")
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return nil, errors.Errorf("failed to get secret %s/%s: %s: %s", namespace, name, resp.Status, strings.TrimSpace(string(body)))
+	}
+	var secret struct {
+		Data map[string][]byte `json:"data"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return nil, errors.Wrap(err, "failed to decode Kubernetes secret")
+	}
+	return secret.Data, nil
+}
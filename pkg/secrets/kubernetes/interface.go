@@ -0,0 +1,8 @@
+package kubernetes
+
+import "context"
+
+// SecretsAPI is the interface for the Kubernetes Secrets API.
+type SecretsAPI interface {
+	GetSecret(ctx context.Context, namespace, name string) (map[string][]byte, error)
+}
@@ -0,0 +1,81 @@
+package kubernetes
+
+import (
+	"context"
+	"strings"
+
+	"secrets-init/pkg/secrets" //nolint:gci
+
+	"github.com/pkg/errors" //nolint:gci
+)
+
+const secretPrefix = "k8s:secret:"
+
+// SecretsProvider Kubernetes secrets provider
+type SecretsProvider struct {
+	api       SecretsAPI
+	namespace string
+}
+
+// NewKubernetesSecretsProvider init Kubernetes Secrets Provider with the pod service account
+func NewKubernetesSecretsProvider() (secrets.Provider, error) {
+	client, err := NewInClusterClient()
+	if err != nil {
+		return nil, err
+	}
+	return &SecretsProvider{api: client, namespace: InClusterNamespace()}, nil
+}
+
+// ResolveSecrets replaces all passed variables values prefixed with 'k8s:secret:'
+// by corresponding secret keys from Kubernetes API
+// The secret reference should be in the format (the pod namespace is used if omitted)
+//
+//	`k8s:secret:{NAMESPACE}/{NAME}#{KEY}`
+//	`k8s:secret:{NAME}#{KEY}`
+func (sp *SecretsProvider) ResolveSecrets(ctx context.Context, vars []string) ([]string, error) {
+	envs := make([]string, 0, len(vars))
+	// secrets fetched so far, so every secret is fetched once
+	fetched := make(map[string]map[string][]byte)
+
+	for _, env := range vars {
+		kv := strings.SplitN(env, "=", 2) //nolint:gomnd
+		if len(kv) != 2 || !strings.HasPrefix(kv[1], secretPrefix) {
+			envs = append(envs, env)
+			continue
+		}
+		namespace, name, key, err := sp.parseReference(strings.TrimPrefix(kv[1], secretPrefix))
+		if err != nil {
+			return vars, err
+		}
+		data, ok := fetched[namespace+"/"+name]
+		if !ok {
+			data, err = sp.api.GetSecret(ctx, namespace, name)
+			if err != nil {
+				return vars, errors.Wrap(err, "failed to get secret from Kubernetes API")
+			}
+			fetched[namespace+"/"+name] = data
+		}
+		value, ok := data[key]
+		if !ok {
+			return vars, errors.Errorf("key %q not found in Kubernetes secret %s/%s", key, namespace, name)
+		}
+		envs = append(envs, kv[0]+"="+string(value))
+	}
+	return envs, nil
+}
+
+// parseReference parses `{NAMESPACE}/{NAME}#{KEY}` secret reference
+func (sp *SecretsProvider) parseReference(ref string) (namespace, name, key string, err error) {
+	path, key, ok := strings.Cut(ref, "#")
+	if !ok || key == "" {
+		return "", "", "", errors.Errorf("missing key in Kubernetes secret reference %q", ref)
+	}
+	namespace, name, ok = strings.Cut(path, "/")
+	if !ok {
+		namespace, name = sp.namespace, path
+	}
+	if namespace == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", "", errors.Errorf("invalid Kubernetes secret reference %q", ref)
+	}
+	return namespace, name, key, nil
+}
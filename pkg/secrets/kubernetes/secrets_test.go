@@ -0,0 +1,140 @@
+// nolint
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAPIServer fake Kubernetes API server serving secrets to requests with the test token
+type fakeAPIServer struct {
+	mu       sync.Mutex
+	secrets  map[string]map[string][]byte
+	requests int
+}
+
+func (s *fakeAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	s.mu.Unlock()
+	if r.Header.Get("Authorization") != "Bearer test-token" {
+		http.Error(w, `{"kind":"Status","reason":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+	data, ok := s.secrets[r.URL.Path]
+	if !ok {
+		http.Error(w, `{"kind":"Status","reason":"Forbidden"}`, http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"kind": "Secret", "data": data})
+}
+
+func TestSecretsProvider_ResolveSecrets(t *testing.T) {
+	tests := []struct {
+		name         string
+		vars         []string
+		want         []string
+		wantRequests int
+		wantErr      bool
+	}{
+		{
+			name: "get secret key from namespace",
+			vars: []string{
+				"test-secret=k8s:secret:test-namespace/test-secret#password",
+				"non-secret=hello",
+			},
+			want: []string{
+				"test-secret=test-secret-value",
+				"non-secret=hello",
+			},
+			wantRequests: 1,
+		},
+		{
+			name: "get secret key from pod namespace",
+			vars: []string{
+				"test-secret=k8s:secret:pod-secret#password",
+			},
+			want: []string{
+				"test-secret=pod-secret-value",
+			},
+			wantRequests: 1,
+		},
+		{
+			name: "get 2 keys of the same secret with one request",
+			vars: []string{
+				"test-user=k8s:secret:test-namespace/test-secret#user",
+				"test-secret=k8s:secret:test-namespace/test-secret#password",
+			},
+			want: []string{
+				"test-user=test-user-value",
+				"test-secret=test-secret-value",
+			},
+			wantRequests: 1,
+		},
+		{
+			name: "error missing key",
+			vars: []string{
+				"test-secret=k8s:secret:test-namespace/test-secret#token",
+			},
+			want: []string{
+				"test-secret=k8s:secret:test-namespace/test-secret#token",
+			},
+			wantRequests: 1,
+			wantErr:      true,
+		},
+		{
+			name: "error forbidden secret",
+			vars: []string{
+				"test-secret=k8s:secret:other-namespace/test-secret#password",
+			},
+			want: []string{
+				"test-secret=k8s:secret:other-namespace/test-secret#password",
+			},
+			wantRequests: 1,
+			wantErr:      true,
+		},
+		{
+			name: "error invalid reference",
+			vars: []string{
+				"test-secret=k8s:secret:test-namespace/test-secret",
+			},
+			want: []string{
+				"test-secret=k8s:secret:test-namespace/test-secret",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPIServer{secrets: map[string]map[string][]byte{
+				"/api/v1/namespaces/test-namespace/secrets/test-secret": {
+					"user":     []byte("test-user-value"),
+					"password": []byte("test-secret-value"),
+				},
+				"/api/v1/namespaces/pod-namespace/secrets/pod-secret": {
+					"password": []byte("pod-secret-value"),
+				},
+			}}
+			srv := httptest.NewServer(api)
+			defer srv.Close()
+			sp := &SecretsProvider{
+				api:       NewClient(srv.URL, "test-token", srv.Client()),
+				namespace: "pod-namespace",
+			}
+			got, err := sp.ResolveSecrets(context.TODO(), tt.vars)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SecretsProvider.ResolveSecrets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantRequests, api.requests)
+		})
+	}
+}
@@ -1,8 +1,119 @@
 package secrets
 
-import "context"
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
 
 // Provider secrets provider interface
 type Provider interface {
 	ResolveSecrets(ctx context.Context, envs []string) ([]string, error)
 }
+
+// VariablesProvider secrets provider reporting the environment variables resolved from every passed variable
+type VariablesProvider interface {
+	Provider
+	// ResolveVariables returns the environment variables resolved from every passed variable, in order;
+	// variables without secret reference are returned unchanged
+	ResolveVariables(ctx context.Context, vars []string) ([][]string, error)
+}
+
+// Flatten returns environment variables resolved from all variables
+func Flatten(resolved [][]string) []string {
+	envs := make([]string, 0, len(resolved))
+	for _, r := range resolved {
+		envs = append(envs, r...)
+	}
+	return envs
+}
+
+// ChainProvider resolves secrets with multiple providers; every provider resolves its own references in the
+// environment variables still holding their original value, so resolved values are never resolved again
+// (e.g. a secret value prefixed with 'exec:')
+type ChainProvider []Provider
+
+// NewChainProvider init chain of providers; nil providers are skipped
+func NewChainProvider(providers ...Provider) Provider {
+	chain := make(ChainProvider, 0, len(providers))
+	for _, p := range providers {
+		if p != nil {
+			chain = append(chain, p)
+		}
+	}
+	return chain
+}
+
+// ResolveSecrets resolves secrets with every provider in order; variables returned by a provider replace the
+// variables with the same name resolved by the previous ones. A failing provider does not stop the chain,
+// so failures of all providers are reported at once: ResolveErrors are merged and other errors are
+// returned with them as ProviderErrors
+func (c ChainProvider) ResolveSecrets(ctx context.Context, vars []string) ([]string, error) {
+	original := make(map[string]bool, len(vars))
+	for _, env := range vars {
+		original[env] = true
+	}
+	envs := vars
+	var resolveErrs []*ResolveError
+	var errs ProviderErrors
+	for _, p := range c {
+		var unresolved, resolved []string
+		for _, env := range envs {
+			if original[env] {
+				unresolved = append(unresolved, env)
+			} else {
+				resolved = append(resolved, env)
+			}
+		}
+		out, err := p.ResolveSecrets(ctx, unresolved)
+		if err != nil {
+			// the next provider resolves the variables passed to the failing one
+			resolveErrs, errs = collectErrors(err, resolveErrs, errs)
+			continue
+		}
+		envs = append(withoutKeys(resolved, out), out...)
+	}
+	if err := NewResolveErrors(resolveErrs); err != nil {
+		errs = append(ProviderErrors{err}, errs...)
+	}
+	switch len(errs) {
+	case 0:
+		return envs, nil
+	case 1:
+		return vars, errs[0]
+	default:
+		return vars, errs
+	}
+}
+
+// collectErrors adds err to the resolve errors or, if it is not a resolve error, to the other errors
+func collectErrors(err error, resolveErrs []*ResolveError, errs ProviderErrors) ([]*ResolveError, ProviderErrors) {
+	var re ResolveErrors
+	var one *ResolveError
+	switch {
+	case errors.As(err, &re):
+		resolveErrs = append(resolveErrs, re...)
+	case errors.As(err, &one):
+		resolveErrs = append(resolveErrs, one)
+	default:
+		errs = append(errs, err)
+	}
+	return resolveErrs, errs
+}
+
+// withoutKeys returns envs without the variables named as one of the variables of other
+func withoutKeys(envs, other []string) []string {
+	keys := make(map[string]bool, len(other))
+	for _, env := range other {
+		key, _, _ := strings.Cut(env, "=")
+		keys[key] = true
+	}
+	kept := make([]string, 0, len(envs))
+	for _, env := range envs {
+		if key, _, _ := strings.Cut(env, "="); !keys[key] {
+			kept = append(kept, env)
+		}
+	}
+	return kept
+}
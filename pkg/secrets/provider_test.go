@@ -0,0 +1,72 @@
+// nolint
+package secrets
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// prefixProvider resolves variables with values prefixed with prefix; failing references fail
+type prefixProvider struct {
+	prefix string
+	err    error
+}
+
+func (p prefixProvider) ResolveSecrets(_ context.Context, vars []string) ([]string, error) {
+	if p.err != nil {
+		return vars, p.err
+	}
+	envs := make([]string, 0, len(vars))
+	var errs []*ResolveError
+	for _, env := range vars {
+		kv := strings.SplitN(env, "=", 2)
+		if strings.HasPrefix(kv[1], p.prefix) {
+			if strings.HasSuffix(kv[1], "failing") {
+				errs = append(errs, &ResolveError{Key: kv[0], Ref: kv[1], Err: errors.New("not found")})
+				continue
+			}
+			env = kv[0] + "=" + strings.TrimPrefix(kv[1], p.prefix) + "-value"
+		}
+		envs = append(envs, env)
+	}
+	if len(errs) > 0 {
+		return vars, NewResolveErrors(errs)
+	}
+	return envs, nil
+}
+
+func TestChainProvider_ResolveSecrets(t *testing.T) {
+	vars := []string{"A=a:one", "B=b:two", "C=hello"}
+	got, err := NewChainProvider(prefixProvider{prefix: "a:"}, nil, prefixProvider{prefix: "b:"}).ResolveSecrets(context.TODO(), vars)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A=one-value", "B=two-value", "C=hello"}, got)
+}
+
+func TestChainProvider_ResolveSecretsErrors(t *testing.T) {
+	vars := []string{"A=a:failing", "B=b:failing", "C=c:three"}
+	providerErr := errors.New("provider error")
+	chain := NewChainProvider(
+		prefixProvider{prefix: "b:"},
+		prefixProvider{prefix: "x:", err: providerErr},
+		prefixProvider{prefix: "a:"},
+		prefixProvider{prefix: "c:"},
+	)
+	got, err := chain.ResolveSecrets(context.TODO(), vars)
+	assert.Equal(t, vars, got)
+
+	// failures of every provider are reported
+	var errs ProviderErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 2)
+	var resolveErrs ResolveErrors
+	require.True(t, errors.As(errs[0], &resolveErrs))
+	require.Len(t, resolveErrs, 2)
+	assert.Equal(t, "A", resolveErrs[0].Key)
+	assert.Equal(t, "B", resolveErrs[1].Key)
+	assert.ErrorIs(t, err, providerErr)
+}
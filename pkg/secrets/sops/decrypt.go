@@ -0,0 +1,100 @@
+package sops
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getsops/sops/v3"
+	"github.com/getsops/sops/v3/aes"
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/getsops/sops/v3/config"
+	"github.com/getsops/sops/v3/keyservice"
+	"github.com/getsops/sops/v3/stores/dotenv"
+	"github.com/getsops/sops/v3/stores/json"
+	"github.com/getsops/sops/v3/stores/yaml"
+	"github.com/pkg/errors" //nolint:gci
+)
+
+// Decrypt decrypts SOPS encrypted file content; the file format ('dotenv', 'json' or 'yaml') is detected
+// from the file name. The data key is decrypted with the master keys only (age, AWS KMS and Google Cloud KMS),
+// and the file MAC is verified.
+func Decrypt(ctx context.Context, name string, data []byte, keys *MasterKeys) (map[string]interface{}, error) {
+	store, err := storeForPath(name)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := store.LoadEncryptedFile(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load SOPS file")
+	}
+	svcs := []keyservice.KeyServiceClient{&keyService{ctx: ctx, keys: keys}}
+	dataKey, err := tree.Metadata.GetDataKeyWithKeyServices(svcs, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt SOPS data key")
+	}
+
+	cipher := aes.NewCipher()
+	mac, err := tree.Decrypt(dataKey, cipher)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt SOPS file")
+	}
+	fileMAC, err := cipher.Decrypt(tree.Metadata.MessageAuthenticationCode, dataKey,
+		tree.Metadata.LastModified.Format(time.RFC3339))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt SOPS MAC")
+	}
+	if fileMAC != mac {
+		return nil, errors.New("SOPS MAC mismatch: the file has been tampered with or corrupted")
+	}
+	if len(tree.Branches) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	doc, _ := plainValue(tree.Branches[0]).(map[string]interface{})
+	return doc, nil
+}
+
+// storeForPath returns SOPS store for the file format detected from the file name
+func storeForPath(name string) (sops.EncryptedFileLoader, error) {
+	cfg := config.NewStoresConfig()
+	switch formats.FormatForPath(name) {
+	case formats.Dotenv:
+		return dotenv.NewStore(&cfg.Dotenv), nil
+	case formats.Json:
+		return json.NewStore(&cfg.JSON), nil
+	case formats.Yaml:
+		return yaml.NewStore(&cfg.YAML), nil
+	default:
+		return nil, errors.Errorf("unsupported SOPS file format %s: expected '.env', '.json' or '.yaml'", name)
+	}
+}
+
+// plainValue converts decrypted SOPS tree value to plain maps and lists; comments are skipped
+func plainValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case sops.TreeBranch:
+		m := make(map[string]interface{}, len(v))
+		for _, item := range v {
+			switch key := item.Key.(type) {
+			case sops.Comment:
+				continue
+			case string:
+				m[key] = plainValue(item.Value)
+			default:
+				m[fmt.Sprint(key)] = plainValue(item.Value)
+			}
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if _, ok := item.(sops.Comment); ok {
+				continue
+			}
+			l = append(l, plainValue(item))
+		}
+		return l
+	default:
+		return v
+	}
+}
@@ -0,0 +1,129 @@
+package sops
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"strings"
+
+	"secrets-init/pkg/secrets/aws" //nolint:gci
+	"secrets-init/pkg/secrets/google"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/getsops/sops/v3/keyservice"
+	"github.com/pkg/errors"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+	"google.golang.org/grpc" //nolint:gci
+)
+
+const arnRegionToken = 3
+
+// AWSKMSProvider provides AWS KMS clients, e.g. aws.SecretsProvider
+type AWSKMSProvider interface {
+	KMS(region string) aws.KMSAPI
+}
+
+// GoogleKMSProvider provides Google Cloud KMS client, e.g. google.SecretsProvider
+type GoogleKMSProvider interface {
+	KMS(ctx context.Context) (google.KMSAPI, error)
+}
+
+// MasterKeys keys used to decrypt the SOPS data key; any of the file master keys is enough
+type MasterKeys struct {
+	AWS    AWSKMSProvider
+	Google GoogleKMSProvider
+	Age    []age.Identity
+}
+
+// keyService SOPS key service decrypting the data key with the master keys, so the secrets providers clients
+// (credentials, endpoints, tracing) are reused; other master keys (PGP, HashiCorp Vault, Azure Key Vault) are
+// not supported
+type keyService struct {
+	ctx  context.Context
+	keys *MasterKeys
+}
+
+// Encrypt is not supported: secrets-init only decrypts SOPS files
+func (ks *keyService) Encrypt(context.Context, *keyservice.EncryptRequest, ...grpc.CallOption) (*keyservice.EncryptResponse, error) {
+	return nil, errors.New("encryption is not supported")
+}
+
+// Decrypt decrypts the SOPS data key with the master key of the request
+func (ks *keyService) Decrypt(_ context.Context, req *keyservice.DecryptRequest, _ ...grpc.CallOption) (*keyservice.DecryptResponse, error) {
+	var dataKey []byte
+	var err error
+	switch k := req.GetKey().GetKeyType().(type) {
+	case *keyservice.Key_AgeKey:
+		if len(ks.keys.Age) == 0 {
+			return nil, errors.New("no age identity")
+		}
+		dataKey, err = ks.keys.decryptAge(string(req.GetCiphertext()))
+	case *keyservice.Key_KmsKey:
+		if ks.keys.AWS == nil {
+			return nil, errors.New("no AWS KMS client")
+		}
+		// the provider credentials are used for all keys
+		if k.KmsKey.GetRole() != "" || k.KmsKey.GetAwsProfile() != "" {
+			return nil, errors.New("AWS KMS keys with role or profile are not supported")
+		}
+		dataKey, err = ks.keys.decryptAWSKMS(ks.ctx, k.KmsKey.GetArn(), string(req.GetCiphertext()), k.KmsKey.GetContext())
+	case *keyservice.Key_GcpKmsKey:
+		if ks.keys.Google == nil {
+			return nil, errors.New("no Google Cloud KMS client")
+		}
+		dataKey, err = ks.keys.decryptGoogleKMS(ks.ctx, k.GcpKmsKey.GetResourceId(), string(req.GetCiphertext()))
+	default:
+		return nil, errors.Errorf("unsupported master key %T", k)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &keyservice.DecryptResponse{Plaintext: dataKey}, nil
+}
+
+func (mk *MasterKeys) decryptAge(enc string) ([]byte, error) {
+	r, err := age.Decrypt(armor.NewReader(strings.NewReader(enc)), mk.Age...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt")
+	}
+	dataKey, err := io.ReadAll(r)
+	return dataKey, errors.Wrap(err, "failed to decrypt")
+}
+
+func (mk *MasterKeys) decryptAWSKMS(ctx context.Context, arn, enc string, encryptionContext map[string]string) ([]byte, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid encrypted data key")
+	}
+	// use the key region
+	var region string
+	if tokens := strings.Split(arn, ":"); len(tokens) > arnRegionToken {
+		region = tokens[arnRegionToken]
+	}
+	out, err := mk.AWS.KMS(region).Decrypt(ctx, &kms.DecryptInput{
+		CiphertextBlob:    ciphertext,
+		EncryptionContext: encryptionContext,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt")
+	}
+	return out.Plaintext, nil
+}
+
+func (mk *MasterKeys) decryptGoogleKMS(ctx context.Context, resourceID, enc string) ([]byte, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid encrypted data key")
+	}
+	client, err := mk.Google.KMS(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	out, err := client.Decrypt(ctx, &kmspb.DecryptRequest{Name: resourceID, Ciphertext: ciphertext})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt")
+	}
+	return out.Plaintext, nil
+}
@@ -0,0 +1,125 @@
+package sops
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+
+	"secrets-init/pkg/secrets" //nolint:gci
+
+	"github.com/pkg/errors" //nolint:gci
+)
+
+const sopsPrefix = "sops:"
+
+// SecretsProvider SOPS encrypted files secrets provider
+type SecretsProvider struct {
+	files []string
+	keys  *MasterKeys
+}
+
+// NewSopsSecretsProvider init SOPS secrets provider; all values of files are merged into the environment
+func NewSopsSecretsProvider(files []string, keys *MasterKeys) secrets.Provider {
+	return &SecretsProvider{files: files, keys: keys}
+}
+
+// ResolveSecrets merges all values of the SOPS files into the passed variables (overriding variables
+// with the same name) and replaces all passed variables values prefixed with 'sops:' by the referenced value
+// of a SOPS file
+//
+//	`sops:/etc/secrets/secrets.enc.env#DB_PASSWORD`
+//	`sops:/etc/secrets/secrets.enc.yaml#db.password`
+//
+// Nested YAML and JSON values are selected with a dot-separated path; objects and arrays are JSON encoded
+func (sp *SecretsProvider) ResolveSecrets(ctx context.Context, vars []string) ([]string, error) {
+	files := &decryptedFiles{ctx: ctx, keys: sp.keys, docs: make(map[string]map[string]interface{})}
+	merged, err := mergeFiles(files, sp.files)
+	if err != nil {
+		return vars, err
+	}
+
+	envs := make([]string, 0, len(vars)+len(merged))
+	for _, env := range vars {
+		kv := strings.SplitN(env, "=", 2) //nolint:gomnd
+		if _, ok := merged[kv[0]]; ok {
+			continue
+		}
+		if len(kv) == 2 && strings.HasPrefix(kv[1], sopsPrefix) {
+			value, err := resolveReference(files, kv[1])
+			if err != nil {
+				return vars, err
+			}
+			env = kv[0] + "=" + value
+		}
+		envs = append(envs, env)
+	}
+
+	keys := make([]string, 0, len(merged))
+	for key := range merged {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		envs = append(envs, key+"="+merged[key])
+	}
+	return envs, nil
+}
+
+// decryptedFiles decrypts SOPS files, so every file is decrypted once
+type decryptedFiles struct {
+	ctx  context.Context
+	keys *MasterKeys
+	docs map[string]map[string]interface{}
+}
+
+// decrypt returns decrypted SOPS file content
+func (f *decryptedFiles) decrypt(path string) (map[string]interface{}, error) {
+	if doc, ok := f.docs[path]; ok {
+		return doc, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read SOPS file")
+	}
+	doc, err := Decrypt(f.ctx, path, data, f.keys)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decrypt SOPS file %s", path)
+	}
+	f.docs[path] = doc
+	return doc, nil
+}
+
+// mergeFiles returns all values of SOPS files by name; values of later files override earlier ones
+func mergeFiles(files *decryptedFiles, paths []string) (map[string]string, error) {
+	merged := make(map[string]string)
+	for _, path := range paths {
+		doc, err := files.decrypt(path)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range doc {
+			if merged[key], err = secrets.FormatValue(value); err != nil {
+				return nil, errors.Wrapf(err, "failed to format %s value from SOPS file %s", key, path)
+			}
+		}
+	}
+	return merged, nil
+}
+
+// resolveReference returns the value selected by 'sops:PATH#KEY' reference
+func resolveReference(files *decryptedFiles, ref string) (string, error) {
+	path, field, ok := strings.Cut(strings.TrimPrefix(ref, sopsPrefix), "#")
+	if !ok || field == "" {
+		return "", errors.Errorf("missing key in SOPS reference %q", ref)
+	}
+	doc, err := files.decrypt(path)
+	if err != nil {
+		return "", err
+	}
+	value, err := secrets.ExtractField(doc, field)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to select key from SOPS file %s", path)
+	}
+	return value, nil
+}
@@ -0,0 +1,220 @@
+// nolint
+package sops
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	secretsage "secrets-init/pkg/secrets/age"
+	"secrets-init/pkg/secrets/aws"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/getsops/sops/v3/keyservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// test fixtures are encrypted with sops and the age key in testdata/age.key, e.g.
+//
+//	sops encrypt --age <recipient> secrets.yaml > testdata/secrets.enc.yaml
+//
+// testdata/key-groups.enc.yaml requires both testdata/age.key and testdata/age-group.key (shamir_threshold: 2)
+
+func ageKeys(t *testing.T) *MasterKeys {
+	identities, err := secretsage.LoadIdentities("testdata/age.key", "")
+	require.NoError(t, err)
+	return &MasterKeys{Age: identities}
+}
+
+func TestSecretsProvider_ResolveSecrets(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []string
+		vars    []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "select values from SOPS YAML file",
+			vars: []string{
+				"test-secret=sops:testdata/secrets.enc.yaml#db.password",
+				"test-port=sops:testdata/secrets.enc.yaml#db.port",
+				"test-enabled=sops:testdata/secrets.enc.yaml#db.enabled",
+				"test-ratio=sops:testdata/secrets.enc.yaml#db.ratio",
+				"test-host=sops:testdata/secrets.enc.yaml#db.hosts.1",
+				"test-plain=sops:testdata/secrets.enc.yaml#db.comment_unencrypted",
+				"non-secret=hello",
+			},
+			want: []string{
+				"test-secret=very-secret-password",
+				"test-port=5432",
+				"test-enabled=true",
+				"test-ratio=0.5",
+				"test-host=db-2",
+				"test-plain=visible",
+				"non-secret=hello",
+			},
+		},
+		{
+			name: "select value from SOPS JSON file",
+			vars: []string{
+				"test-secret=sops:testdata/secrets.enc.json#db.password",
+			},
+			want: []string{
+				"test-secret=very-secret-password",
+			},
+		},
+		{
+			name: "select value from SOPS file with MAC of encrypted values only",
+			vars: []string{
+				"test-secret=sops:testdata/mac-only-encrypted.enc.yaml#db.password",
+			},
+			want: []string{
+				"test-secret=very-secret-password",
+			},
+		},
+		{
+			name: "select values from SOPS file with comments and encrypted_regex",
+			vars: []string{
+				"test-secret=sops:testdata/comments.enc.yaml#db.password",
+				"test-user=sops:testdata/comments.enc.yaml#db.user",
+				"test-hosts=sops:testdata/comments.enc.yaml#db.hosts",
+				"test-key=sops:testdata/comments.enc.yaml#api_key",
+			},
+			want: []string{
+				"test-secret=very-secret-password",
+				"test-user=admin",
+				`test-hosts=["db-1","db-2"]`,
+				"test-key=key-123456789",
+			},
+		},
+		{
+			name:  "merge SOPS dotenv file",
+			files: []string{"testdata/secrets.enc.env"},
+			vars: []string{
+				"API_KEY=to-be-replaced",
+				"non-secret=hello",
+			},
+			want: []string{
+				"non-secret=hello",
+				"API_KEY=key-123456789",
+				"DB_PASSWORD=very-secret-password",
+			},
+		},
+		{
+			name: "error missing key",
+			vars: []string{
+				"test-secret=sops:testdata/secrets.enc.yaml#db.token",
+			},
+			want: []string{
+				"test-secret=sops:testdata/secrets.enc.yaml#db.token",
+			},
+			wantErr: true,
+		},
+		{
+			name: "error missing file",
+			vars: []string{
+				"test-secret=sops:testdata/missing.enc.yaml#db.password",
+			},
+			want: []string{
+				"test-secret=sops:testdata/missing.enc.yaml#db.password",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := NewSopsSecretsProvider(tt.files, ageKeys(t))
+			got, err := sp.ResolveSecrets(context.TODO(), tt.vars)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SecretsProvider.ResolveSecrets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecrypt_Tampered(t *testing.T) {
+	data, err := os.ReadFile("testdata/secrets.enc.yaml")
+	require.NoError(t, err)
+	// unencrypted values are protected by the MAC
+	tampered := strings.Replace(string(data), "comment_unencrypted: visible", "comment_unencrypted: changed", 1)
+	_, err = Decrypt(context.TODO(), "secrets.enc.yaml", []byte(tampered), ageKeys(t))
+	assert.ErrorContains(t, err, "MAC mismatch")
+}
+
+func TestDecrypt_WrongKey(t *testing.T) {
+	data, err := os.ReadFile("testdata/secrets.enc.yaml")
+	require.NoError(t, err)
+	identities, err := secretsage.LoadIdentities("", "AGE-SECRET-KEY-1GFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPQ4EGAEX")
+	require.NoError(t, err)
+	_, err = Decrypt(context.TODO(), "secrets.enc.yaml", data, &MasterKeys{Age: identities})
+	assert.ErrorContains(t, err, "failed to decrypt SOPS data key")
+}
+
+func TestDecrypt_UnsupportedFormat(t *testing.T) {
+	_, err := Decrypt(context.TODO(), "secrets.enc.ini", []byte("[db]\npassword = ENC[...]"), ageKeys(t))
+	assert.ErrorContains(t, err, "unsupported SOPS file format")
+}
+
+func TestDecrypt_KeyGroups(t *testing.T) {
+	data, err := os.ReadFile("testdata/key-groups.enc.yaml")
+	require.NoError(t, err)
+	// the data key is split between two key groups and both are required
+	_, err = Decrypt(context.TODO(), "key-groups.enc.yaml", data, ageKeys(t))
+	assert.ErrorContains(t, err, "failed to decrypt SOPS data key")
+
+	keys := ageKeys(t)
+	identities, err := secretsage.LoadIdentities("testdata/age-group.key", "")
+	require.NoError(t, err)
+	keys.Age = append(keys.Age, identities...)
+	got, err := Decrypt(context.TODO(), "key-groups.enc.yaml", data, keys)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"db": map[string]interface{}{"password": "very-secret-password"}}, got)
+}
+
+// fakeKMS returns the data key for the expected ciphertext
+type fakeKMS struct {
+	region  string
+	dataKey []byte
+}
+
+func (f *fakeKMS) KMS(region string) aws.KMSAPI {
+	f.region = region
+	return f
+}
+
+func (f *fakeKMS) Decrypt(_ context.Context, input *kms.DecryptInput, _ ...func(*kms.Options)) (*kms.DecryptOutput, error) {
+	if string(input.CiphertextBlob) != "encrypted-data-key" || input.EncryptionContext["app"] != "test" {
+		return nil, os.ErrPermission
+	}
+	return &kms.DecryptOutput{Plaintext: f.dataKey}, nil
+}
+
+func TestDecrypt_AWSKMS(t *testing.T) {
+	data, err := os.ReadFile("testdata/secrets.enc.yaml")
+	require.NoError(t, err)
+	store, err := storeForPath("secrets.enc.yaml")
+	require.NoError(t, err)
+	tree, err := store.LoadEncryptedFile(data)
+	require.NoError(t, err)
+	dataKey, err := tree.Metadata.GetDataKeyWithKeyServices([]keyservice.KeyServiceClient{&keyService{ctx: context.TODO(), keys: ageKeys(t)}}, nil)
+	require.NoError(t, err)
+
+	// replace the age master key with an AWS KMS master key
+	withKMS := strings.Replace(string(data), "    kms: []", `    kms:
+        - arn: arn:aws:kms:eu-west-1:123456789012:key/test-key
+          context:
+            app: test
+          created_at: "2026-10-18T15:26:34Z"
+          enc: ZW5jcnlwdGVkLWRhdGEta2V5`, 1)
+	fake := &fakeKMS{dataKey: dataKey}
+	got, err := Decrypt(context.TODO(), filepath.Join("testdata", "secrets.enc.yaml"), []byte(withKMS), &MasterKeys{AWS: fake})
+	require.NoError(t, err)
+	assert.Equal(t, "key-123456789", got["api_key"])
+	assert.Equal(t, "eu-west-1", fake.region)
+}
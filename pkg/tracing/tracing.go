@@ -0,0 +1,158 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "secrets-init"
+
+// span attribute keys
+const (
+	ProviderKey = attribute.Key("secrets.provider")
+	RegionKey   = semconv.CloudRegionKey
+	ProjectKey  = attribute.Key("gcp.project.id")
+	NameKey     = attribute.Key("secrets.name")
+	// ErrorTypeKey and ErrorCountKey describe failures without the error message
+	ErrorTypeKey  = attribute.Key("error.type")
+	ErrorCountKey = attribute.Key("secrets.error.count")
+)
+
+// recordNames allows recording secret names as span attributes
+var recordNames bool
+
+// Options tracing options
+type Options struct {
+	// Endpoint OTLP/HTTP collector host:port; the OTEL_EXPORTER_OTLP_* environment variables are used if empty
+	Endpoint string
+	// Insecure disables TLS for the collector connection
+	Insecure bool
+	// RecordNames records secret names as span attributes
+	RecordNames bool
+	// Version service version
+	Version string
+}
+
+// Setup init OTLP trace exporter and register it as global tracer provider;
+// the returned function flushes pending spans and must be called before exit
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	var clientOpts []otlptracehttp.Option
+	if opts.Endpoint != "" {
+		clientOpts = append(clientOpts, otlptracehttp.WithEndpoint(opts.Endpoint))
+	}
+	if opts.Insecure {
+		clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+	}
+	var exporter sdktrace.SpanExporter
+	exporter, err := otlptracehttp.New(ctx, clientOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create OTLP trace exporter")
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceNameKey.String(tracerName),
+		semconv.ServiceVersionKey.String(opts.Version),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create trace resource")
+	}
+	if !opts.RecordNames {
+		exporter = redactingExporter{exporter}
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	recordNames = opts.RecordNames
+	return tp.Shutdown, nil
+}
+
+// Start starts a new span; a no-op span is returned when tracing is not set up
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// SecretName returns secret name span attributes, or nothing if recording secret names is not allowed
+func SecretName(name string) []attribute.KeyValue {
+	if !recordNames {
+		return nil
+	}
+	return []attribute.KeyValue{NameKey.String(name)}
+}
+
+// End records err (if any) and ends span; error messages contain secret names and references, so unless
+// recording secret names is allowed only the error type and the number of failures are recorded
+func End(span trace.Span, err error) {
+	if err != nil {
+		if recordNames {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else {
+			cause, count := errorCause(err)
+			span.SetAttributes(ErrorTypeKey.String(fmt.Sprintf("%T", cause)), ErrorCountKey.Int(count))
+			span.SetStatus(codes.Error, "")
+		}
+	}
+	span.End()
+}
+
+// errorCause returns the innermost error of a wrapped errors chain (stopping at errors joining multiple errors)
+// and the number of joined errors
+func errorCause(err error) (error, int) {
+	for {
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			return err, len(joined.Unwrap())
+		}
+		next := errors.Unwrap(err)
+		if next == nil {
+			return err, 1
+		}
+		err = next
+	}
+}
+
+// redactingExporter removes status descriptions and recorded exceptions from spans before export: the gRPC
+// and HTTP instrumentations record the status message of failed calls (e.g. Secret Manager NOT_FOUND), which
+// contains the secret name
+type redactingExporter struct {
+	sdktrace.SpanExporter
+}
+
+// ExportSpans exports redacted spans
+func (e redactingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	redacted := make([]sdktrace.ReadOnlySpan, 0, len(spans))
+	for _, span := range spans {
+		redacted = append(redacted, redactedSpan{span})
+	}
+	return e.SpanExporter.ExportSpans(ctx, redacted) //nolint:wrapcheck
+}
+
+// redactedSpan span without status description and exception events
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+// Status returns span status without description
+func (s redactedSpan) Status() sdktrace.Status {
+	return sdktrace.Status{Code: s.ReadOnlySpan.Status().Code}
+}
+
+// Events returns span events without exceptions
+func (s redactedSpan) Events() []sdktrace.Event {
+	var events []sdktrace.Event
+	for _, event := range s.ReadOnlySpan.Events() {
+		if event.Name != semconv.ExceptionEventName {
+			events = append(events, event)
+		}
+	}
+	return events
+}
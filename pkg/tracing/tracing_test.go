@@ -0,0 +1,104 @@
+// nolint
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"secrets-init/pkg/secrets"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// collectorStub fake OTLP/HTTP collector recording received trace requests
+type collectorStub struct {
+	mu       sync.Mutex
+	requests int
+	bodies   []byte
+}
+
+func (c *collectorStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if r.Method == http.MethodPost && r.URL.Path == "/v1/traces" {
+		c.requests++
+		c.bodies = append(c.bodies, body...)
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name        string
+		recordNames bool
+		wantName    bool
+	}{
+		{
+			name:     "secret names are not recorded by default",
+			wantName: false,
+		},
+		{
+			name:        "secret names are recorded when allowed",
+			recordNames: true,
+			wantName:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer otel.SetTracerProvider(noop.NewTracerProvider())
+			collector := &collectorStub{}
+			srv := httptest.NewServer(collector)
+			defer srv.Close()
+
+			ctx := context.TODO()
+			shutdown, err := Setup(ctx, Options{
+				Endpoint:    strings.TrimPrefix(srv.URL, "http://"),
+				Insecure:    true,
+				RecordNames: tt.recordNames,
+			})
+			assert.NoError(t, err)
+
+			ctx, span := Start(ctx, "aws.ResolveSecrets", ProviderKey.String("aws"), RegionKey.String("us-east-1"))
+			_, child := Start(ctx, "secretsmanager.GetSecretValue", SecretName("test-secret-name")...)
+			End(child, errors.New("test error"))
+			// instrumentation libraries record the status message of failed calls
+			_, rpc := otel.Tracer("test").Start(ctx, "google.cloud.secretmanager.v1.SecretManagerService/AccessSecretVersion")
+			rpc.SetStatus(codes.Error, "Secret [projects/123/secrets/test-secret-name] not found or has no versions.")
+			rpc.RecordError(errors.New("rpc error: test-secret-name not found"))
+			rpc.End()
+			// resolve errors contain secret names and references
+			End(span, secrets.NewResolveErrors([]*secrets.ResolveError{
+				{Key: "TEST_SECRET", Ref: "test-secret-name", Err: errors.New("not found")},
+				{Key: "OTHER_SECRET", Ref: "other-secret", Err: errors.New("not found")},
+			}))
+			assert.NoError(t, shutdown(ctx))
+
+			collector.mu.Lock()
+			defer collector.mu.Unlock()
+			assert.Equal(t, 1, collector.requests)
+			body := string(collector.bodies)
+			assert.Contains(t, body, "aws.ResolveSecrets")
+			assert.Contains(t, body, "secretsmanager.GetSecretValue")
+			assert.Contains(t, body, "SecretManagerService/AccessSecretVersion")
+			assert.Contains(t, body, "us-east-1")
+			assert.Equal(t, tt.wantName, strings.Contains(body, "test-secret-name"))
+			assert.Equal(t, tt.wantName, strings.Contains(body, "test error"))
+			if !tt.wantName {
+				// only the error type and count are recorded otherwise
+				assert.Contains(t, body, "secrets.ResolveErrors")
+				assert.Contains(t, body, string(ErrorCountKey))
+			}
+		})
+	}
+}